@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"terminal/internal/config"
+	"terminal/internal/storage/backend"
+	"terminal/internal/terminal"
+	"terminal/internal/terminal/dataset"
+	"text/tabwriter"
+)
+
+// result keeps outcomes of all simulated games for the strategy.
+type result struct {
+	strategy terminal.Strategy
+	attempts map[int]int // key: attempts to find the target, value: amount of games
+	games    int
+	total    int
+	worst    int
+	skipped  int
+}
+
+func main() {
+	path := flag.String("dataset", "", "path to dataset JSON export. If empty, dataset is loaded from the database, configured by CONFIG_PATH")
+	budget := flag.Int("budget", 4, "amount of attempts, the game is considered solved within")
+	flag.Parse()
+
+	data, err := loadDataset(*path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not load dataset: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Simulating %d games\n\n", len(data.Games))
+
+	results := make([]*result, 0)
+	for _, strategy := range terminal.Strategies() {
+		results = append(results, simulate(data, strategy))
+	}
+
+	printResults(results, *budget)
+}
+
+func loadDataset(path string) (*dataset.Dataset, error) {
+	if path == "" {
+		conf := config.MustLoad()
+
+		ctx := context.Background()
+
+		st, err := backend.New(ctx, conf)
+		if err != nil {
+			return nil, err
+		}
+
+		return st.GetDataset(ctx)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var data dataset.Dataset
+	if err = json.Unmarshal(content, &data); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func simulate(data *dataset.Dataset, strategy terminal.Strategy) *result {
+	r := &result{
+		strategy: strategy,
+		attempts: make(map[int]int),
+	}
+
+	for _, game := range data.Games {
+		attempts, err := terminal.Simulate(game.Words, game.Target, terminal.WithStrategy(strategy))
+		if err != nil {
+			r.skipped++
+			continue
+		}
+
+		n := len(attempts)
+		r.attempts[n]++
+		r.games++
+		r.total += n
+		if n > r.worst {
+			r.worst = n
+		}
+	}
+
+	return r
+}
+
+func printResults(results []*result, budget int) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(writer, "strategy\tgames\tskipped\tmean\tworst\twithin %d\n", budget)
+	for _, r := range results {
+		mean := 0.0
+		solved := 0.0
+		if r.games != 0 {
+			mean = float64(r.total) / float64(r.games)
+
+			within := 0
+			for n, count := range r.attempts {
+				if n <= budget {
+					within += count
+				}
+			}
+			solved = float64(within) / float64(r.games) * 100
+		}
+
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%.3f\t%d\t%.2f%%\n", r.strategy.Name(), r.games, r.skipped, mean, r.worst, solved)
+	}
+	writer.Flush()
+
+	for _, r := range results {
+		fmt.Printf("\n%s attempts histogram\n", r.strategy.Name())
+
+		attempts := make([]int, 0, len(r.attempts))
+		for n := range r.attempts {
+			attempts = append(attempts, n)
+		}
+		sort.Ints(attempts)
+
+		for _, n := range attempts {
+			bar := strings.Repeat("#", r.attempts[n]*50/r.games)
+			fmt.Printf(" %2d | %-50s %d\n", n, bar, r.attempts[n])
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"terminal/internal/config"
+	"terminal/internal/storage"
+	"terminal/internal/storage/backend"
+)
+
+// runAudit checks stored games for consistency, and optionally flags or quarantines games with problems.
+func runAudit(ctx context.Context, conf *config.Config, args []string) error {
+	flags := flag.NewFlagSet("audit", flag.ExitOnError)
+	flagGames := flags.Bool("flag", false, "flag games with problems, so they are not used to find answers")
+	quarantine := flags.Bool("quarantine", false, "move games with problems to the quarantine table")
+	verbose := flags.Bool("v", false, "print every found issue")
+	flags.Parse(args)
+
+	if *flagGames && *quarantine {
+		return errors.New("-flag and -quarantine could not be used together")
+	}
+
+	st, err := backend.New(ctx, conf)
+	if err != nil {
+		return err
+	}
+
+	games, err := st.GetAllGames(ctx)
+	if err != nil {
+		return err
+	}
+
+	report := storage.Audit(games)
+
+	fmt.Printf("Audited %d games, found %d issues in %d games\n", report.TotalGames, len(report.Issues), len(report.GameIDs()))
+	for _, problem := range []storage.Problem{storage.ProblemTargetNotInWords, storage.ProblemMixedLengths, storage.ProblemConflictingTarget} {
+		fmt.Printf(" - %s: %d\n", problem, report.Count(problem))
+	}
+
+	if *verbose {
+		fmt.Println()
+		for _, issue := range report.Issues {
+			fmt.Printf("%s\t%s\t%s\n", issue.GameID, issue.Problem, issue.Details)
+		}
+	}
+
+	ids := report.GameIDs()
+	if len(ids) == 0 {
+		return nil
+	}
+
+	switch {
+	case *flagGames:
+		if err = st.FlagGames(ctx, ids); err != nil {
+			return err
+		}
+		fmt.Printf("\nFlagged %d games\n", len(ids))
+	case *quarantine:
+		if err = st.QuarantineGames(ctx, ids); err != nil {
+			return err
+		}
+		fmt.Printf("\nQuarantined %d games\n", len(ids))
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"terminal/internal/config"
+	"terminal/internal/storage/backend"
+	"terminal/internal/terminal/dataset"
+)
+
+// runImport merges dataset exports into the database. Format of each file is detected by its extension.
+func runImport(ctx context.Context, conf *config.Config, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	verbose := flags.Bool("v", false, "print every conflicting game")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: import [-v] <dataset>...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no dataset files provided")
+	}
+
+	games := make([]dataset.Game, 0)
+	for _, path := range flags.Args() {
+		data, err := readDataset(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		fmt.Printf("Read %d games from %s\n", len(data.Games), path)
+		games = append(games, data.Games...)
+	}
+
+	st, err := backend.New(ctx, conf)
+	if err != nil {
+		return err
+	}
+
+	summary, err := st.ImportGames(ctx, games)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nImported %d games\n", len(games))
+	fmt.Printf(" - added: %d\n", summary.Added)
+	fmt.Printf(" - skipped: %d\n", summary.Skipped)
+	fmt.Printf(" - conflicting: %d\n", len(summary.Conflicting))
+	fmt.Printf(" - users added: %d\n", summary.UsersAdded)
+
+	if *verbose && len(summary.Conflicting) != 0 {
+		fmt.Println()
+		for _, game := range summary.Conflicting {
+			fmt.Printf("%s\t%s\t@%s\t%s\n", game.CreatedAt.Format("2006-01-02 15:04:05"), game.Target, game.User.Username, strings.Join(game.Words, " "))
+		}
+	}
+
+	return nil
+}
+
+func readDataset(path string) (*dataset.Dataset, error) {
+	format := dataset.FormatJSON
+	if extension := strings.TrimPrefix(filepath.Ext(path), "."); extension != "" {
+		var err error
+		format, err = dataset.ParseFormat(extension)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return dataset.Read(file, format)
+}
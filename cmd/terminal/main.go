@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 	"terminal/internal/config"
 	"terminal/internal/ocr"
-	"terminal/internal/storage/postgres"
+	"terminal/internal/storage/backend"
 	"terminal/internal/telegram"
 	"terminal/pkg/log"
 	"terminal/pkg/log/sl"
@@ -12,9 +17,33 @@ import (
 	"github.com/robfig/cron/v3"
 )
 
+// commands are maintenance subcommands of the binary. Without a subcommand the bot is started.
+var commands = map[string]func(ctx context.Context, conf *config.Config, args []string) error{
+	"audit":   runAudit,
+	"import":  runImport,
+	"migrate": runMigrate,
+}
+
 func main() {
 	conf := config.MustLoad()
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if len(os.Args) > 1 {
+		command, exists := commands[os.Args[1]]
+		if !exists {
+			fmt.Fprintf(os.Stderr, "unknown command: %s\n", os.Args[1])
+			os.Exit(2)
+		}
+
+		if err := command(ctx, conf, os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", os.Args[1], err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	logger := log.Init(conf.Env)
 
 	if conf.Env == config.EnvProduction {
@@ -27,12 +56,12 @@ func main() {
 		c.Start()
 	}
 
-	storage, err := postgres.New(conf.Postgres)
+	storage, err := backend.New(ctx, conf)
 	if err != nil {
-		logger.Error("failed to connect to postgres database", sl.Err(err))
+		logger.Error("failed to open storage", slog.String("driver", conf.Storage.Driver), sl.Err(err))
 		os.Exit(1)
 	}
 
-	bot := telegram.New(logger, conf.Telegram, storage, ocr.New(conf.OCR.Tokens))
-	bot.Run()
+	bot := telegram.New(logger, conf.Telegram, storage, ocr.New(conf.OCR.Tokens, conf.OCR.Language), conf.Dataset)
+	bot.Run(ctx)
 }
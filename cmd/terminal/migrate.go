@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"terminal/internal/config"
+	"terminal/internal/storage/backend"
+)
+
+// runMigrate applies, reverts or lists database migrations. The bot applies migrations on start by itself.
+func runMigrate(ctx context.Context, conf *config.Config, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	steps := flags.Int("steps", 1, "amount of migrations to revert with down")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: migrate [-steps n] up|down|status")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("expected exactly one of up, down or status")
+	}
+
+	runner, err := backend.Migrator(ctx, conf)
+	if err != nil {
+		return err
+	}
+	defer runner.Close()
+
+	switch flags.Arg(0) {
+	case "up":
+		applied, err := runner.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("Applied %s\n", migration)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("Database is up to date")
+		}
+	case "down":
+		if *steps < 1 {
+			return errors.New("-steps must be positive")
+		}
+		reverted, err := runner.Down(ctx, *steps)
+		for _, migration := range reverted {
+			fmt.Printf("Reverted %s\n", migration)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("No applied migrations")
+		}
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			if status.Applied {
+				fmt.Printf("%-24s applied at %s\n", status.Migration, status.AppliedAt.Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Printf("%-24s pending\n", status.Migration)
+			}
+		}
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown migrate command: %s", flags.Arg(0))
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
@@ -18,8 +19,11 @@ const (
 type Config struct {
 	Env      string   `yaml:"env" env-required:"true"`
 	Telegram Telegram `yaml:"telegram"`
+	Storage  Storage  `yaml:"storage"`
 	Postgres Postgres `yaml:"postgres"`
+	SQLite   SQLite   `yaml:"sqlite"`
 	OCR      OCR      `yaml:"ocr"`
+	Dataset  Dataset  `yaml:"dataset"`
 }
 
 // Telegram represents structure with credentials for Telegram bot connection
@@ -27,6 +31,14 @@ type Telegram struct {
 	Token string `yaml:"token"`
 }
 
+// Storage represents structure with settings of games storage
+type Storage struct {
+	Driver string `yaml:"driver" env-default:"postgres"` // postgres | sqlite | memory
+	// QueryTimeout limits a single storage call, BulkTimeout limits dataset exports, audit, import and word stats
+	QueryTimeout time.Duration `yaml:"query_timeout" env-default:"5s"`
+	BulkTimeout  time.Duration `yaml:"bulk_timeout" env-default:"10m"`
+}
+
 // Pstgres represents structure with credentials for PostgreSQL database
 type Postgres struct {
 	Host     string `yaml:"host"`
@@ -37,9 +49,24 @@ type Postgres struct {
 	ModeSSL  string `yaml:"sslmode"`
 }
 
+// SQLite represents structure with settings of SQLite database, used with sqlite storage driver
+type SQLite struct {
+	Path string `yaml:"path" env-default:"terminal.db"`
+}
+
 // OCR represents structure with credentials for OCR service (ocr.space currently)
 type OCR struct {
 	Tokens []string `yaml:"tokens"`
+	// Language is ocr.space code of the language of word lists on screenshots, e.g. eng, rus or ger
+	Language string `yaml:"language" env-default:"eng"`
+}
+
+// Dataset represents settings of anonymised dataset exports
+type Dataset struct {
+	Salt     string `yaml:"salt"`
+	MinGames int    `yaml:"min_games" env-default:"5"`
+	// ExactTime keeps games creation time in anonymised exports, instead of truncating it to the day
+	ExactTime bool `yaml:"exact_time"`
 }
 
 // MustLoad loads config to a new Config instance and return it's pointer.
@@ -14,10 +14,16 @@ import (
 	"strings"
 	"terminal/pkg/slice"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
+// DefaultLanguage is used, when the language of word lists is not configured.
+const DefaultLanguage = "eng"
+
 type Client struct {
-	tokens []string
+	tokens   []string
+	language string
 }
 
 type responseOCR struct {
@@ -34,8 +40,15 @@ type response struct {
 	err  error
 }
 
-func New(tokens []string) *Client {
-	return &Client{tokens}
+// New creates a client, that recognizes text in language, given as ocr.space language code, e.g. eng, rus or ger.
+func New(tokens []string, language string) *Client {
+	if language == "" {
+		language = DefaultLanguage
+	}
+	return &Client{
+		tokens:   tokens,
+		language: language,
+	}
 }
 
 func (c *Client) ExtractWords(ctx context.Context, filepath string) ([]string, error) {
@@ -117,7 +130,7 @@ func (c *Client) formRequest(path string) (*http.Request, error) {
 		return nil, err
 	}
 
-	writer.WriteField("language", "eng")
+	writer.WriteField("language", c.language)
 	writer.WriteField("isOverlayRequired", "true")
 
 	err = writer.Close()
@@ -143,7 +156,7 @@ func findWords(text string) []string {
 	lines := strings.Split(text, "\r\n")
 
 	for _, line := range lines {
-		word := strings.TrimSpace(line)
+		word := strings.ToLower(strings.TrimSpace(line))
 
 		if !isWord(word) {
 			continue
@@ -161,7 +174,7 @@ func findWords(text string) []string {
 func findCorrectLength(words []string) int {
 	lengthCount := make(map[int]int)
 	for _, word := range words {
-		length := len(word)
+		length := utf8.RuneCountInString(word)
 		lengthCount[length]++
 	}
 
@@ -180,7 +193,7 @@ func findCorrectLength(words []string) int {
 func filterWordsByLength(words []string, correctLength int) []string {
 	var filteredWords []string
 	for _, word := range words {
-		if len(word) == correctLength {
+		if utf8.RuneCountInString(word) == correctLength {
 			filteredWords = append(filteredWords, word)
 		}
 	}
@@ -188,12 +201,12 @@ func filterWordsByLength(words []string, correctLength int) []string {
 }
 
 func isWord(word string) bool {
-	if len(word) < 4 {
+	if utf8.RuneCountInString(word) < 4 {
 		return false
 	}
 
 	for _, char := range word {
-		if char < 'a' || char > 'z' {
+		if !unicode.IsLetter(char) {
 			return false
 		}
 	}
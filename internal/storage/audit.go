@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// Problem is a kind of inconsistency, found in a stored game.
+type Problem string
+
+const (
+	ProblemTargetNotInWords  Problem = "target-not-in-words"
+	ProblemMixedLengths      Problem = "mixed-lengths"
+	ProblemConflictingTarget Problem = "conflicting-target"
+)
+
+// Issue is a problem, found in the stored game.
+type Issue struct {
+	GameID  string
+	Problem Problem
+	Details string
+}
+
+// AuditReport is a result of stored games consistency audit.
+type AuditReport struct {
+	TotalGames int
+	Issues     []Issue
+}
+
+// Audit checks stored games and classifies found problems: targets, that are not in the word list,
+// word lists of mixed lengths, and games with the same word list, but different targets.
+// In the last case only games with less common targets are reported.
+func Audit(games []Game) *AuditReport {
+	report := &AuditReport{
+		TotalGames: len(games),
+		Issues:     make([]Issue, 0),
+	}
+
+	byHash := make(map[string][]Game)
+	for _, game := range games {
+		byHash[game.WordsHash] = append(byHash[game.WordsHash], game)
+
+		if !containsWord(game.Words, game.Target) {
+			report.Issues = append(report.Issues, Issue{
+				GameID:  game.ID,
+				Problem: ProblemTargetNotInWords,
+				Details: fmt.Sprintf("target %q is not in the word list", game.Target),
+			})
+		}
+
+		if !isEqualLength(game.Words) {
+			report.Issues = append(report.Issues, Issue{
+				GameID:  game.ID,
+				Problem: ProblemMixedLengths,
+				Details: "word list consists of words of different length",
+			})
+		}
+	}
+
+	hashes := make([]string, 0, len(byHash))
+	for hash := range byHash {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+
+	for _, hash := range hashes {
+		report.Issues = append(report.Issues, conflictingTargets(byHash[hash])...)
+	}
+
+	return report
+}
+
+// Count returns amount of issues with the problem.
+func (r *AuditReport) Count(problem Problem) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Problem == problem {
+			n++
+		}
+	}
+	return n
+}
+
+// GameIDs returns unique IDs of games with at least one issue.
+func (r *AuditReport) GameIDs() []string {
+	ids := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, issue := range r.Issues {
+		if _, exists := seen[issue.GameID]; exists {
+			continue
+		}
+		seen[issue.GameID] = struct{}{}
+		ids = append(ids, issue.GameID)
+	}
+	return ids
+}
+
+// conflictingTargets reports games with the same word list, which target differs from the most common one.
+// If there is no single most common target, all games are reported.
+func conflictingTargets(games []Game) []Issue {
+	counts := make(map[string]int)
+	for _, game := range games {
+		counts[game.Target]++
+	}
+	if len(counts) < 2 {
+		return nil
+	}
+
+	common := ""
+	max := 0
+	tie := false
+	for target, count := range counts {
+		switch {
+		case count > max:
+			common, max, tie = target, count, false
+		case count == max:
+			tie = true
+		}
+	}
+
+	issues := make([]Issue, 0)
+	for _, game := range games {
+		if !tie && game.Target == common {
+			continue
+		}
+		issues = append(issues, Issue{
+			GameID:  game.ID,
+			Problem: ProblemConflictingTarget,
+			Details: fmt.Sprintf("%d games with the same word list have %d different targets", len(games), len(counts)),
+		})
+	}
+
+	return issues
+}
+
+func containsWord(words []string, target string) bool {
+	for _, word := range words {
+		if word == target {
+			return true
+		}
+	}
+	return false
+}
+
+func isEqualLength(words []string) bool {
+	for _, word := range words {
+		if utf8.RuneCountInString(word) != utf8.RuneCountInString(words[0]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+// Package backend opens the storage, selected by the storage.driver config key.
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"terminal/internal/config"
+	"terminal/internal/storage"
+	"terminal/internal/storage/memory"
+	"terminal/internal/storage/migrate"
+	"terminal/internal/storage/postgres"
+	"terminal/internal/storage/sqlite"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+var ErrNoMigrations = errors.New("0xterminal.storage: memory storage has no migrations")
+
+// New opens the storage with configured driver. The context limits only connecting and applying migrations.
+func New(ctx context.Context, conf *config.Config) (storage.Storage, error) {
+	timeouts := storage.Timeouts{
+		Query: conf.Storage.QueryTimeout,
+		Bulk:  conf.Storage.BulkTimeout,
+	}
+
+	switch conf.Storage.Driver {
+	case DriverPostgres, "":
+		return postgres.New(ctx, conf.Postgres, timeouts)
+	case DriverSQLite:
+		return sqlite.New(ctx, conf.SQLite, timeouts)
+	case DriverMemory:
+		return memory.New(), nil
+	}
+	return nil, fmt.Errorf("0xterminal.storage: unknown storage driver %q", conf.Storage.Driver)
+}
+
+// Migrator opens the database of configured driver without applying migrations.
+func Migrator(ctx context.Context, conf *config.Config) (*migrate.Runner, error) {
+	switch conf.Storage.Driver {
+	case DriverPostgres, "":
+		return postgres.Migrator(ctx, conf.Postgres)
+	case DriverSQLite:
+		return sqlite.Migrator(ctx, conf.SQLite)
+	case DriverMemory:
+		return nil, ErrNoMigrations
+	}
+	return nil, fmt.Errorf("0xterminal.storage: unknown storage driver %q", conf.Storage.Driver)
+}
@@ -0,0 +1,590 @@
+// Package memory implements storage.Storage in memory. It is intended for local runs and tests,
+// where running PostgreSQL is overkill. All data is lost, when the process exits.
+package memory
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"sync"
+	"terminal/internal/storage"
+	"terminal/internal/terminal"
+	"terminal/internal/terminal/dataset"
+	"time"
+)
+
+// ErrDuplicateAttempt is returned, when attempts of the game have the same number, like primary key violation in SQL storages.
+var ErrDuplicateAttempt = errors.New("0xterminal.storage: duplicate attempt number")
+
+type Storage struct {
+	mu         sync.RWMutex
+	users      []storage.User
+	games      []storage.Game
+	quarantine []storage.Game
+	attempts   map[string][]storage.Attempt // key: game ID
+	// quarantinedAttempts are attempts of quarantined games. key: game ID
+	quarantinedAttempts map[string][]storage.Attempt
+}
+
+func New() *Storage {
+	return &Storage{
+		attempts:            make(map[string][]storage.Attempt),
+		quarantinedAttempts: make(map[string][]storage.Attempt),
+	}
+}
+
+func (s *Storage) SaveUser(ctx context.Context, telegramID int64, username string, firstname string, lastname string) (*storage.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, err := s.saveUser(telegramID, username, firstname, lastname)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (s *Storage) GetUserByTelegramID(ctx context.Context, telegramID int64) (*storage.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, exists := s.user(telegramID)
+	if !exists {
+		return nil, storage.ErrUserNotFound
+	}
+
+	return &user, nil
+}
+
+func (s *Storage) SaveGame(ctx context.Context, telegramID int64, words []string, target string, attemptsAmount int, attempts []storage.Attempt) (*storage.Game, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.user(telegramID); !exists {
+		return nil, storage.ErrUserNotFound
+	}
+
+	numbers := make(map[int]struct{}, len(attempts))
+	for _, attempt := range attempts {
+		if _, exists := numbers[attempt.Number]; exists {
+			return nil, ErrDuplicateAttempt
+		}
+		numbers[attempt.Number] = struct{}{}
+	}
+
+	game := s.saveGame(telegramID, words, target, attemptsAmount, time.Now())
+	s.saveAttempts(game.ID, attempts)
+
+	return &game, nil
+}
+
+func (s *Storage) TryFindAnswer(ctx context.Context, words []string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	wordsHash := terminal.ComputeWordsHash(copyWords(words))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, game := range s.games {
+		if game.WordsHash == wordsHash && !game.Flagged {
+			return game.Target, nil
+		}
+	}
+
+	return "", storage.ErrGameNotFound
+}
+
+func (s *Storage) FindCandidates(ctx context.Context, words []string, available []string, threshold float64) ([]storage.Candidate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return storage.RankCandidates(words, s.games, threshold, available), nil
+}
+
+func (s *Storage) GetDataset(ctx context.Context) (*dataset.Dataset, error) {
+	games := make([]dataset.Game, 0)
+	err := s.StreamDataset(ctx, dataset.Filter{}, func(game dataset.Game) error {
+		games = append(games, game)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var data dataset.Dataset
+
+	data.Games = games
+	data.TotalGames = len(games)
+
+	return &data, nil
+}
+
+func (s *Storage) StreamDataset(ctx context.Context, filter dataset.Filter, fn func(dataset.Game) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	games := make([]dataset.Game, 0)
+	for _, game := range s.games {
+		user, exists := s.user(game.TelegramID)
+		if !exists {
+			continue
+		}
+
+		data := dataset.Game{
+			Words:          copyWords(game.Words),
+			Target:         game.Target,
+			AttemptsAmount: game.AttemptsAmount,
+			User: dataset.User{
+				TelegramID: user.TelegramID,
+				Username:   user.Username,
+			},
+			WordsHash: game.WordsHash,
+			CreatedAt: game.CreatedAt,
+		}
+		for _, attempt := range s.attempts[game.ID] {
+			data.Attempts = append(data.Attempts, dataset.Attempt{
+				Word:           attempt.Word,
+				GuessedLetters: attempt.GuessedLetters,
+			})
+		}
+
+		if filter.Match(data) {
+			games = append(games, data)
+		}
+	}
+	// fn is called without the lock, so it could use the storage
+	s.mu.RUnlock()
+
+	sort.SliceStable(games, func(i, j int) bool {
+		return games[i].CreatedAt.After(games[j].CreatedAt)
+	})
+
+	for _, game := range games {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(game); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) GetAllGames(ctx context.Context) ([]storage.Game, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var games []storage.Game
+	for _, game := range s.games {
+		game.Words = copyWords(game.Words)
+		games = append(games, game)
+	}
+
+	return games, nil
+}
+
+func (s *Storage) GetDailyReport(ctx context.Context, date time.Time) (*storage.DailyReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	end := start.AddDate(0, 0, 1)
+	during := func(t time.Time) bool {
+		return !t.Before(start) && t.Before(end)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, game := range s.games {
+		user, exists := s.user(game.TelegramID)
+		if exists && during(game.CreatedAt) {
+			counts[user.Username]++
+		}
+	}
+
+	var userStats []storage.UserStat
+	for username, played := range counts {
+		userStats = append(userStats, storage.UserStat{
+			Username:    username,
+			GamesPlayed: played,
+		})
+	}
+	sortUserStats(userStats)
+
+	var usersJoined []string
+	for _, user := range s.users {
+		if during(user.CreatedAt) {
+			usersJoined = append(usersJoined, user.Username)
+		}
+	}
+
+	var report storage.DailyReport
+	report.Stats = userStats
+	report.JoinedUsers = usersJoined
+
+	return &report, nil
+}
+
+func (s *Storage) GetGamesToUserStatistics(ctx context.Context) ([]storage.UserStat, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[int64]int)
+	for _, game := range s.games {
+		counts[game.TelegramID]++
+	}
+
+	stats := make([]storage.UserStat, 0)
+	for _, user := range s.users {
+		stats = append(stats, storage.UserStat{
+			Username:    user.Username,
+			GamesPlayed: counts[user.TelegramID],
+		})
+	}
+	sortUserStats(stats)
+
+	return stats, nil
+}
+
+func (s *Storage) GetUsersCount(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.users), nil
+}
+
+func (s *Storage) GetWordStats(ctx context.Context) ([]storage.WordStat, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	indexes := make(map[string]int)
+	stats := make([]storage.WordStat, 0)
+	for _, game := range s.games {
+		if game.Flagged {
+			continue
+		}
+		for _, word := range game.Words {
+			i, exists := indexes[word]
+			if !exists {
+				i = len(stats)
+				indexes[word] = i
+				stats = append(stats, storage.WordStat{Word: word})
+			}
+
+			stats[i].Appearances++
+			if word == game.Target {
+				stats[i].Targets++
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+func (s *Storage) FlagGames(ctx context.Context, ids []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.games {
+		if slices.Contains(ids, s.games[i].ID) {
+			s.games[i].Flagged = true
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) QuarantineGames(ctx context.Context, ids []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	games := make([]storage.Game, 0, len(s.games))
+	for _, game := range s.games {
+		if !slices.Contains(ids, game.ID) {
+			games = append(games, game)
+			continue
+		}
+
+		game.Flagged = false
+		s.quarantine = append(s.quarantine, game)
+		if attempts, exists := s.attempts[game.ID]; exists {
+			s.quarantinedAttempts[game.ID] = attempts
+			delete(s.attempts, game.ID)
+		}
+	}
+	s.games = games
+
+	return nil
+}
+
+func (s *Storage) DeleteGame(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, game := range s.games {
+		if game.ID == id {
+			s.games = slices.Delete(s.games, i, i+1)
+			delete(s.attempts, id)
+			return nil
+		}
+	}
+
+	return storage.ErrGameNotFound
+}
+
+func (s *Storage) GetRandomGame(ctx context.Context) (*storage.Game, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	candidates := make([]storage.Game, 0)
+	for _, game := range s.games {
+		if !game.Flagged && slices.Contains(game.Words, game.Target) {
+			candidates = append(candidates, game)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, storage.ErrGameNotFound
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(candidates))))
+	if err != nil {
+		return nil, err
+	}
+
+	game := candidates[n.Int64()]
+	game.Words = copyWords(game.Words)
+
+	return &game, nil
+}
+
+func (s *Storage) GetAttempts(ctx context.Context, gameID string) ([]storage.Attempt, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	attempts := make([]storage.Attempt, len(s.attempts[gameID]))
+	copy(attempts, s.attempts[gameID])
+
+	return attempts, nil
+}
+
+func (s *Storage) ImportGames(ctx context.Context, games []dataset.Game) (*storage.ImportSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var summary storage.ImportSummary
+
+	for _, game := range games {
+		if _, exists := s.user(game.User.TelegramID); !exists {
+			_, err := s.saveUser(game.User.TelegramID, game.User.Username, "", "")
+			if err != nil { // username is taken by another user
+				summary.Conflicting = append(summary.Conflicting, game)
+				continue
+			}
+			summary.UsersAdded++
+		}
+
+		wordsHash := terminal.ComputeWordsHash(copyWords(game.Words))
+
+		duplicate, conflicting := false, false
+		for _, stored := range s.games {
+			if stored.WordsHash != wordsHash {
+				continue
+			}
+			if stored.Target == game.Target && stored.TelegramID == game.User.TelegramID && stored.CreatedAt.Equal(game.CreatedAt) {
+				duplicate = true
+			}
+			if stored.Target != game.Target && !stored.Flagged {
+				conflicting = true
+			}
+		}
+
+		switch {
+		case duplicate:
+			summary.Skipped++
+		case conflicting:
+			summary.Conflicting = append(summary.Conflicting, game)
+		default:
+			saved := s.saveGame(game.User.TelegramID, game.Words, game.Target, game.AttemptsAmount, game.CreatedAt)
+
+			attempts := make([]storage.Attempt, len(game.Attempts))
+			for i, attempt := range game.Attempts {
+				attempts[i] = storage.Attempt{
+					Number:         i + 1,
+					Word:           attempt.Word,
+					GuessedLetters: attempt.GuessedLetters,
+				}
+			}
+			s.saveAttempts(saved.ID, attempts)
+
+			summary.Added++
+		}
+	}
+
+	return &summary, nil
+}
+
+// user returns the user with the telegram ID. The caller must hold the lock.
+func (s *Storage) user(telegramID int64) (storage.User, bool) {
+	for _, user := range s.users {
+		if user.TelegramID == telegramID {
+			return user, true
+		}
+	}
+	return storage.User{}, false
+}
+
+// saveUser stores the user, if both telegram ID and username are not taken. The caller must hold the write lock.
+func (s *Storage) saveUser(telegramID int64, username string, firstname string, lastname string) (storage.User, error) {
+	for _, user := range s.users {
+		if user.TelegramID == telegramID || user.Username == username {
+			return storage.User{}, storage.ErrUserAlreadyExists
+		}
+	}
+
+	user := storage.User{
+		ID:         newID(),
+		TelegramID: telegramID,
+		Username:   username,
+		FirstName:  firstname,
+		LastName:   lastname,
+		CreatedAt:  time.Now(),
+	}
+	s.users = append(s.users, user)
+
+	return user, nil
+}
+
+// saveGame stores the game. The caller must hold the write lock.
+func (s *Storage) saveGame(telegramID int64, words []string, target string, attemptsAmount int, createdAt time.Time) storage.Game {
+	game := storage.Game{
+		ID:             newID(),
+		TelegramID:     telegramID,
+		Words:          copyWords(words),
+		Target:         target,
+		AttemptsAmount: attemptsAmount,
+		WordsHash:      terminal.ComputeWordsHash(copyWords(words)),
+		CreatedAt:      createdAt,
+	}
+	s.games = append(s.games, game)
+
+	game.Words = copyWords(words)
+
+	return game
+}
+
+// saveAttempts stores attempts of the game ordered by their numbers. The caller must hold the write lock.
+func (s *Storage) saveAttempts(gameID string, attempts []storage.Attempt) {
+	for _, attempt := range attempts {
+		attempt.GameID = gameID
+		s.attempts[gameID] = append(s.attempts[gameID], attempt)
+	}
+	sort.SliceStable(s.attempts[gameID], func(i, j int) bool {
+		return s.attempts[gameID][i].Number < s.attempts[gameID][j].Number
+	})
+}
+
+func (s *Storage) gameExists(id string) bool {
+	for _, game := range s.games {
+		if game.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// sortUserStats orders statistics by amount of games played, as postgres storage does.
+func sortUserStats(stats []storage.UserStat) {
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].GamesPlayed != stats[j].GamesPlayed {
+			return stats[i].GamesPlayed > stats[j].GamesPlayed
+		}
+		return stats[i].Username < stats[j].Username
+	})
+}
+
+func copyWords(words []string) []string {
+	copied := make([]string, len(words))
+	copy(copied, words)
+	return copied
+}
+
+// newID returns a random UUID v4, as postgres uuid_generate_v4() does.
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+var _ storage.Storage = (*Storage)(nil)
@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"terminal/internal/storage"
+	"terminal/internal/storage/memory"
+	"terminal/internal/storage/storagetest"
+	"testing"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(*testing.T) storage.Storage {
+		return memory.New()
+	})
+}
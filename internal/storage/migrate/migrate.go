@@ -0,0 +1,267 @@
+// Package migrate applies SQL migrations, embedded into the binary, and tracks them in schema_migrations table.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrInvalidName  = errors.New("migrate.Load(): invalid migration file name")
+	ErrIrreversible = errors.New("migrate.Runner.Down(): migration has no down file")
+)
+
+// Migration is a pair of `<version>_<name>.up.sql` and `<version>_<name>.down.sql` files.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// Status is a migration with time, it was applied at, if it was.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Locker guards migrations from being applied by several processes at once. Lock and Unlock are called on the same connection.
+type Locker interface {
+	Lock(ctx context.Context, conn *sql.Conn) error
+	Unlock(ctx context.Context, conn *sql.Conn) error
+}
+
+// Runner applies migrations to the database.
+type Runner struct {
+	db         *sqlx.DB
+	migrations []Migration
+	locker     Locker
+}
+
+// New loads migrations from the root of fsys. Locker could be nil, if the database serializes migrations itself.
+func New(db *sqlx.DB, fsys fs.FS, locker Locker) (*Runner, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Runner{
+		db:         db,
+		migrations: migrations,
+		locker:     locker,
+	}, nil
+}
+
+// Load reads migrations from the root of fsys, ordered by version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		base, direction, found := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), ".")
+		number, name, separated := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if !found || !separated || err != nil || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidName, entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("%w: %s has no up file", ErrInvalidName, migration)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies all migrations, that are not applied yet, and returns them.
+func (r *Runner) Up(ctx context.Context) ([]Migration, error) {
+	applied := make([]Migration, 0)
+	err := r.withLock(ctx, func(ctx context.Context, conn *sql.Conn) error {
+		versions, err := r.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range r.migrations {
+			if _, exists := versions[migration.Version]; exists {
+				continue
+			}
+
+			insert := r.db.Rebind("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)")
+			err = r.apply(ctx, conn, migration.Up, insert, migration.Version, migration.Name, time.Now().UTC())
+			if err != nil {
+				return fmt.Errorf("migration %s: %w", migration, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations and returns them.
+func (r *Runner) Down(ctx context.Context, steps int) ([]Migration, error) {
+	reverted := make([]Migration, 0)
+	err := r.withLock(ctx, func(ctx context.Context, conn *sql.Conn) error {
+		versions, err := r.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(r.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := r.migrations[i]
+			if _, exists := versions[migration.Version]; !exists {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("%w: %s", ErrIrreversible, migration)
+			}
+
+			err = r.apply(ctx, conn, migration.Down, r.db.Rebind("DELETE FROM schema_migrations WHERE version = ?"), migration.Version)
+			if err != nil {
+				return fmt.Errorf("migration %s: %w", migration, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status returns all known migrations, and whether they are applied.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	statuses := make([]Status, 0, len(r.migrations))
+	err := r.withLock(ctx, func(ctx context.Context, conn *sql.Conn) error {
+		versions, err := r.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range r.migrations {
+			appliedAt, applied := versions[migration.Version]
+			statuses = append(statuses, Status{
+				Migration: migration,
+				Applied:   applied,
+				AppliedAt: appliedAt,
+			})
+		}
+		return nil
+	})
+
+	return statuses, err
+}
+
+// Close closes the database, migrations are applied to.
+func (r *Runner) Close() error {
+	return r.db.Close()
+}
+
+// withLock calls fn on a dedicated connection, holding the lock, and makes sure schema_migrations table exists.
+func (r *Runner) withLock(ctx context.Context, fn func(ctx context.Context, conn *sql.Conn) error) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if r.locker != nil {
+		if err = r.locker.Lock(ctx, conn); err != nil {
+			return err
+		}
+		// the lock must be released, even if ctx is canceled, since the connection returns to the pool
+		defer r.locker.Unlock(context.WithoutCancel(ctx), conn)
+	}
+
+	query := "CREATE TABLE IF NOT EXISTS schema_migrations (version integer NOT NULL PRIMARY KEY, name text NOT NULL, applied_at timestamp NOT NULL)"
+	if _, err = conn.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	return fn(ctx, conn)
+}
+
+// applied returns applied migrations versions with time, they were applied at.
+func (r *Runner) applied(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	versions := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err = rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		versions[version] = appliedAt
+	}
+
+	return versions, rows.Err()
+}
+
+// apply runs the migration script and updates schema_migrations in one transaction.
+func (r *Runner) apply(ctx context.Context, conn *sql.Conn, script string, query string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -1,13 +1,18 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"terminal/internal/config"
 	"terminal/internal/storage"
+	"terminal/internal/storage/migrate"
 	"terminal/internal/terminal"
 	"terminal/internal/terminal/dataset"
+	"terminal/migrations"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -16,28 +21,87 @@ import (
 )
 
 type Storage struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	timeouts storage.Timeouts
 }
 
-func New(conf config.Postgres) (*Storage, error) {
-	db, err := sqlx.Open("postgres", fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s",
-		conf.Host, conf.Port, conf.User, conf.Name, conf.Password, conf.ModeSSL))
+// migrationsLock is a key of advisory lock, that is held while migrations are applied.
+const migrationsLock = 0x0e7e4a1
+
+// New connects to the database and applies migrations, that are not applied yet.
+func New(ctx context.Context, conf config.Postgres, timeouts storage.Timeouts) (*Storage, error) {
+	db, err := connect(ctx, dataSourceName(conf))
 	if err != nil {
 		return nil, err
 	}
 
-	if err = db.Ping(); err != nil {
+	return open(ctx, db, timeouts)
+}
+
+// open applies migrations, that are not applied yet, to the connected database.
+func open(ctx context.Context, db *sqlx.DB, timeouts storage.Timeouts) (*Storage, error) {
+	runner, err := migrate.New(db, migrations.Postgres, locker{})
+	if err != nil {
 		return nil, err
 	}
 
+	if _, err = runner.Up(ctx); err != nil {
+		return nil, fmt.Errorf("could not apply migrations: %w", err)
+	}
+
 	return &Storage{
-		db: db,
+		db:       db,
+		timeouts: timeouts,
 	}, nil
 }
 
-func (s *Storage) SaveUser(telegramID int64, username string, firstname string, lastname string) (*storage.User, error) {
+// Migrator connects to the database without applying migrations, so they could be managed manually.
+func Migrator(ctx context.Context, conf config.Postgres) (*migrate.Runner, error) {
+	db, err := connect(ctx, dataSourceName(conf))
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.New(db, migrations.Postgres, locker{})
+}
+
+func dataSourceName(conf config.Postgres) string {
+	return fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s",
+		conf.Host, conf.Port, conf.User, conf.Name, conf.Password, conf.ModeSSL)
+}
+
+func connect(ctx context.Context, dsn string) (*sqlx.DB, error) {
+	db, err := sqlx.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = db.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// locker holds postgres advisory lock, so several instances of the bot don't apply migrations at once.
+type locker struct{}
+
+func (locker) Lock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationsLock)
+	return err
+}
+
+func (locker) Unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationsLock)
+	return err
+}
+
+func (s *Storage) SaveUser(ctx context.Context, telegramID int64, username string, firstname string, lastname string) (*storage.User, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
 	query := "INSERT INTO users (telegram_id, username, firstname, lastname) VALUES ($1, $2, $3, $4) RETURNING *"
-	row := s.db.QueryRow(query, telegramID, username, firstname, lastname)
+	row := s.db.QueryRowContext(ctx, query, telegramID, username, firstname, lastname)
 
 	if row.Err() != nil {
 		return nil, row.Err()
@@ -55,9 +119,12 @@ func (s *Storage) SaveUser(telegramID int64, username string, firstname string,
 	return &user, nil
 }
 
-func (s *Storage) GetUserByTelegramID(telegramID int64) (*storage.User, error) {
+func (s *Storage) GetUserByTelegramID(ctx context.Context, telegramID int64) (*storage.User, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
 	var user storage.User
-	err := s.db.QueryRow("SELECT * FROM users WHERE telegram_id = $1", telegramID).Scan(&user.ID, &user.TelegramID, &user.Username, &user.FirstName, &user.LastName, &user.IsAdmin, &user.CreatedAt)
+	err := s.db.QueryRowContext(ctx, "SELECT * FROM users WHERE telegram_id = $1", telegramID).Scan(&user.ID, &user.TelegramID, &user.Username, &user.FirstName, &user.LastName, &user.IsAdmin, &user.CreatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, storage.ErrUserNotFound
 	}
@@ -68,31 +135,56 @@ func (s *Storage) GetUserByTelegramID(telegramID int64) (*storage.User, error) {
 	return &user, nil
 }
 
-func (s *Storage) SaveGame(telegramID int64, words []string, target string, attemptsAmount int) (*storage.Game, error) {
-	query := "INSERT INTO games (telegram_id, words, target, attempts_amount, words_hash) VALUES ($1, $2, $3, $4, $5) RETURNING *"
-	wordsHash := terminal.ComputeWordsHash(words)
+func (s *Storage) SaveGame(ctx context.Context, telegramID int64, words []string, target string, attemptsAmount int, attempts []storage.Attempt) (*storage.Game, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
 
-	row := s.db.QueryRow(query, telegramID, pq.Array(words), target, attemptsAmount, wordsHash)
-
-	if row.Err() != nil {
-		return nil, row.Err()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO games (telegram_id, words, target, attempts_amount, words_hash) VALUES ($1, $2, $3, $4, $5) RETURNING id, telegram_id, words, target, attempts_amount, words_hash, flagged, created_at"
+	wordsHash := terminal.ComputeWordsHash(copyWords(words))
 
 	var game storage.Game
 	var pqWords pq.StringArray
-	err := row.Scan(&game.ID, &game.TelegramID, &pqWords, &game.Target, &game.WordsHash, &game.CreatedAt)
+	err = tx.QueryRowContext(ctx, query, telegramID, pq.Array(words), target, attemptsAmount, wordsHash).
+		Scan(&game.ID, &game.TelegramID, &pqWords, &game.Target, &game.AttemptsAmount, &game.WordsHash, &game.Flagged, &game.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
 	game.Words = words
 
-	return &game, err
+	query = "INSERT INTO game_attempts (game_id, number, word, guessed_letters) VALUES ($1, $2, $3, $4)"
+	for _, attempt := range attempts {
+		_, err = tx.ExecContext(ctx, query, game.ID, attempt.Number, attempt.Word, attempt.GuessedLetters)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &game, nil
 }
 
-func (s *Storage) TryFindAnswer(words []string) (string, error) {
-	wordsHash := terminal.ComputeWordsHash(words)
+func (s *Storage) TryFindAnswer(ctx context.Context, words []string) (string, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	wordsHash := terminal.ComputeWordsHash(copyWords(words))
 
-	query := "SELECT target FROM games WHERE words_hash = $1"
+	query := "SELECT target FROM games WHERE words_hash = $1 AND NOT flagged"
 
 	var target string
-	err := s.db.QueryRow(query, wordsHash).Scan(&target)
+	err := s.db.QueryRowContext(ctx, query, wordsHash).Scan(&target)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", storage.ErrGameNotFound
+	}
 	if err != nil {
 		return "", err
 	}
@@ -100,26 +192,32 @@ func (s *Storage) TryFindAnswer(words []string) (string, error) {
 	return target, nil
 }
 
-func (s *Storage) GetDataset() (*dataset.Dataset, error) {
-	query := "SELECT games.words, games.target, games.attempts_amount, games.words_hash, games.created_at, users.username, users.telegram_id FROM games JOIN users ON games.telegram_id = users.telegram_id ORDER BY games.created_at DESC"
+func (s *Storage) FindCandidates(ctx context.Context, words []string, available []string, threshold float64) ([]storage.Candidate, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	query := `
+        SELECT id, telegram_id, words, target, attempts_amount, words_hash, flagged, created_at FROM games
+        WHERE NOT flagged AND target = ANY($2) AND words && $1
+            AND (SELECT count(DISTINCT word) FROM unnest(words) AS word WHERE word = ANY($1)) >= $3`
 
-	rows, err := s.db.Query(query)
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(words), pq.Array(available), storage.MinOverlap(words, threshold))
 	if err != nil {
 		return nil, err
 	}
 
 	defer rows.Close()
 
-	var games []dataset.Game
+	var games []storage.Game
 
 	for rows.Next() {
-		var game dataset.Game
-		var words pq.StringArray
-		err = rows.Scan(&words, &game.Target, &game.AttemptsAmount, &game.WordsHash, &game.CreatedAt, &game.User.Username, &game.User.TelegramID)
+		var game storage.Game
+		var list pq.StringArray
+		err = rows.Scan(&game.ID, &game.TelegramID, &list, &game.Target, &game.AttemptsAmount, &game.WordsHash, &game.Flagged, &game.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
-		game.Words = []string(words)
+		game.Words = []string(list)
 
 		games = append(games, game)
 	}
@@ -128,6 +226,19 @@ func (s *Storage) GetDataset() (*dataset.Dataset, error) {
 		return nil, err
 	}
 
+	return storage.RankCandidates(words, games, threshold, available), nil
+}
+
+func (s *Storage) GetDataset(ctx context.Context) (*dataset.Dataset, error) {
+	games := make([]dataset.Game, 0)
+	err := s.StreamDataset(ctx, dataset.Filter{}, func(game dataset.Game) error {
+		games = append(games, game)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	var data dataset.Dataset
 
 	data.Games = games
@@ -136,10 +247,80 @@ func (s *Storage) GetDataset() (*dataset.Dataset, error) {
 	return &data, nil
 }
 
-func (s *Storage) GetAllGames() ([]storage.Game, error) {
-	query := "SELECT * FROM games"
+func (s *Storage) StreamDataset(ctx context.Context, filter dataset.Filter, fn func(dataset.Game) error) error {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Bulk)
+	defer cancel()
+
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if !filter.From.IsZero() {
+		where("games.created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		where("games.created_at < $%d", filter.To)
+	}
+	if filter.TelegramID != 0 {
+		where("games.telegram_id = $%d", filter.TelegramID)
+	}
+	if filter.WordLength != 0 {
+		where("char_length(games.words[1]) = $%d", filter.WordLength)
+	}
+	if filter.MinWords != 0 {
+		where("cardinality(games.words) >= $%d", filter.MinWords)
+	}
+
+	query := `
+        SELECT games.words, games.target, games.attempts_amount, games.words_hash, games.created_at, users.username, users.telegram_id,
+            (SELECT json_agg(json_build_object('word', a.word, 'guessed_letters', a.guessed_letters) ORDER BY a.number) FROM game_attempts a WHERE a.game_id = games.id)
+        FROM games JOIN users ON games.telegram_id = users.telegram_id`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY games.created_at DESC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var game dataset.Game
+		var words pq.StringArray
+		var attempts []byte
+		err = rows.Scan(&words, &game.Target, &game.AttemptsAmount, &game.WordsHash, &game.CreatedAt, &game.User.Username, &game.User.TelegramID, &attempts)
+		if err != nil {
+			return err
+		}
+		game.Words = []string(words)
+
+		if attempts != nil {
+			if err = json.Unmarshal(attempts, &game.Attempts); err != nil {
+				return err
+			}
+		}
+
+		if err = fn(game); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *Storage) GetAllGames(ctx context.Context) ([]storage.Game, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Bulk)
+	defer cancel()
 
-	rows, err := s.db.Query(query)
+	query := "SELECT id, telegram_id, words, target, attempts_amount, words_hash, flagged, created_at FROM games"
+
+	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -151,7 +332,7 @@ func (s *Storage) GetAllGames() ([]storage.Game, error) {
 	for rows.Next() {
 		var game storage.Game
 		var words pq.StringArray
-		err = rows.Scan(&game.ID, &game.TelegramID, &words, &game.Target, &game.AttemptsAmount, &game.WordsHash, &game.CreatedAt)
+		err = rows.Scan(&game.ID, &game.TelegramID, &words, &game.Target, &game.AttemptsAmount, &game.WordsHash, &game.Flagged, &game.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -167,7 +348,10 @@ func (s *Storage) GetAllGames() ([]storage.Game, error) {
 	return games, nil
 }
 
-func (s *Storage) GetDailyReport(date time.Time) (*storage.DailyReport, error) {
+func (s *Storage) GetDailyReport(ctx context.Context, date time.Time) (*storage.DailyReport, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
 	end := date.AddDate(0, 0, 1)
 
 	startDate := date.Format("2006-01-02")
@@ -181,7 +365,7 @@ func (s *Storage) GetDailyReport(date time.Time) (*storage.DailyReport, error) {
         GROUP BY u.username
         ORDER BY played_today DESC`
 
-	rows, err := s.db.Query(query, startDate, endDate)
+	rows, err := s.db.QueryContext(ctx, query, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -204,7 +388,7 @@ func (s *Storage) GetDailyReport(date time.Time) (*storage.DailyReport, error) {
         FROM users
         WHERE created_at >= $1 AND created_at < $2`
 
-	rows, err = s.db.Query(query, startDate, endDate)
+	rows, err = s.db.QueryContext(ctx, query, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -229,10 +413,13 @@ func (s *Storage) GetDailyReport(date time.Time) (*storage.DailyReport, error) {
 	return &report, nil
 }
 
-func (s *Storage) GetGamesToUserStatistics() ([]storage.UserStat, error) {
+func (s *Storage) GetGamesToUserStatistics(ctx context.Context) ([]storage.UserStat, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
 	query := "SELECT u.username, COUNT(g.id) AS games_played FROM users u LEFT JOIN games g ON u.telegram_id = g.telegram_id GROUP BY u.username ORDER BY games_played DESC"
 
-	rows, err := s.db.Query(query)
+	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -256,14 +443,247 @@ func (s *Storage) GetGamesToUserStatistics() ([]storage.UserStat, error) {
 	return stats, nil
 }
 
-func (s *Storage) GetUsersCount() (int, error) {
+func (s *Storage) GetUsersCount(ctx context.Context) (int, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
 	query := "SELECT COUNT(*) FROM users"
 
 	var usersAmount int
-	err := s.db.QueryRow(query).Scan(&usersAmount)
+	err := s.db.QueryRowContext(ctx, query).Scan(&usersAmount)
 	if err != nil {
 		return 0, err
 	}
 
 	return usersAmount, nil
 }
+
+func (s *Storage) GetWordStats(ctx context.Context) ([]storage.WordStat, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Bulk)
+	defer cancel()
+
+	query := `
+        SELECT w.word, COUNT(*) AS appearances, COUNT(*) FILTER (WHERE w.word = g.target) AS targets
+        FROM games g, unnest(g.words) AS w(word)
+        WHERE NOT g.flagged
+        GROUP BY w.word`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	stats := make([]storage.WordStat, 0)
+	for rows.Next() {
+		var stat storage.WordStat
+		err = rows.Scan(&stat.Word, &stat.Appearances, &stat.Targets)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (s *Storage) FlagGames(ctx context.Context, ids []string) error {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	query := "UPDATE games SET flagged = true WHERE id = ANY($1)"
+
+	_, err := s.db.ExecContext(ctx, query, pq.Array(ids))
+	return err
+}
+
+func (s *Storage) QuarantineGames(ctx context.Context, ids []string) error {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO games_quarantine (id, telegram_id, words, target, attempts_amount, words_hash, created_at)
+        SELECT id, telegram_id, words, target, attempts_amount, words_hash, created_at FROM games WHERE id = ANY($1)
+        ON CONFLICT (id) DO NOTHING`
+
+	_, err = tx.ExecContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+
+	// attempts are moved too, since they are deleted with the game, so the quarantine could be undone
+	query = `
+        INSERT INTO game_attempts_quarantine (game_id, number, word, guessed_letters)
+        SELECT game_id, number, word, guessed_letters FROM game_attempts WHERE game_id = ANY($1)
+        ON CONFLICT (game_id, number) DO NOTHING`
+
+	_, err = tx.ExecContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM games WHERE id = ANY($1)", pq.Array(ids))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Storage) DeleteGame(ctx context.Context, id string) error {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	// attempts are deleted by the foreign key cascade
+	result, err := s.db.ExecContext(ctx, "DELETE FROM games WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return storage.ErrGameNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) GetRandomGame(ctx context.Context) (*storage.Game, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	query := "SELECT id, telegram_id, words, target, attempts_amount, words_hash, flagged, created_at FROM games WHERE NOT flagged AND target = ANY(words) ORDER BY random() LIMIT 1"
+
+	var game storage.Game
+	var words pq.StringArray
+	err := s.db.QueryRowContext(ctx, query).Scan(&game.ID, &game.TelegramID, &words, &game.Target, &game.AttemptsAmount, &game.WordsHash, &game.Flagged, &game.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrGameNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	game.Words = []string(words)
+
+	return &game, nil
+}
+
+func (s *Storage) GetAttempts(ctx context.Context, gameID string) ([]storage.Attempt, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	query := "SELECT game_id, number, word, guessed_letters FROM game_attempts WHERE game_id = $1 ORDER BY number"
+
+	attempts := make([]storage.Attempt, 0)
+	err := s.db.SelectContext(ctx, &attempts, query, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+func (s *Storage) ImportGames(ctx context.Context, games []dataset.Game) (*storage.ImportSummary, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Bulk)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var summary storage.ImportSummary
+
+	users := make(map[int64]bool) // value: user exists
+	for _, game := range games {
+		exists, checked := users[game.User.TelegramID]
+		if !checked {
+			result, err := tx.ExecContext(ctx, "INSERT INTO users (telegram_id, username) VALUES ($1, $2) ON CONFLICT DO NOTHING", game.User.TelegramID, game.User.Username)
+			if err != nil {
+				return nil, err
+			}
+			if added, _ := result.RowsAffected(); added != 0 {
+				summary.UsersAdded++
+			}
+
+			err = tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE telegram_id = $1)", game.User.TelegramID).Scan(&exists)
+			if err != nil {
+				return nil, err
+			}
+			users[game.User.TelegramID] = exists
+		}
+
+		if !exists { // username is taken by another user
+			summary.Conflicting = append(summary.Conflicting, game)
+			continue
+		}
+
+		wordsHash := terminal.ComputeWordsHash(copyWords(game.Words))
+
+		var duplicate bool
+		query := "SELECT EXISTS (SELECT 1 FROM games WHERE words_hash = $1 AND target = $2 AND telegram_id = $3 AND created_at = $4)"
+		err = tx.QueryRowContext(ctx, query, wordsHash, game.Target, game.User.TelegramID, game.CreatedAt).Scan(&duplicate)
+		if err != nil {
+			return nil, err
+		}
+		if duplicate {
+			summary.Skipped++
+			continue
+		}
+
+		var conflicting bool
+		query = "SELECT EXISTS (SELECT 1 FROM games WHERE words_hash = $1 AND target <> $2 AND NOT flagged)"
+		err = tx.QueryRowContext(ctx, query, wordsHash, game.Target).Scan(&conflicting)
+		if err != nil {
+			return nil, err
+		}
+		if conflicting {
+			summary.Conflicting = append(summary.Conflicting, game)
+			continue
+		}
+
+		var id string
+		query = "INSERT INTO games (telegram_id, words, target, attempts_amount, words_hash, created_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id"
+		err = tx.QueryRowContext(ctx, query, game.User.TelegramID, pq.Array(game.Words), game.Target, game.AttemptsAmount, wordsHash, game.CreatedAt).Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, attempt := range game.Attempts {
+			query = "INSERT INTO game_attempts (game_id, number, word, guessed_letters) VALUES ($1, $2, $3, $4)"
+			_, err = tx.ExecContext(ctx, query, id, i+1, attempt.Word, attempt.GuessedLetters)
+			if err != nil {
+				return nil, err
+			}
+		}
+		summary.Added++
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// copyWords returns a copy of the word list, so it could be sorted for hashing without changing the caller's list.
+func copyWords(words []string) []string {
+	copied := make([]string, len(words))
+	copy(copied, words)
+	return copied
+}
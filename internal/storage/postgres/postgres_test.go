@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"terminal/internal/storage"
+	"terminal/internal/storage/storagetest"
+	"testing"
+	"time"
+)
+
+// TestStorage runs the conformance suite against the database from TERMINAL_TEST_POSTGRES_DSN.
+// All data in the database is removed before each test.
+func TestStorage(t *testing.T) {
+	dsn := os.Getenv("TERMINAL_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TERMINAL_TEST_POSTGRES_DSN is not set")
+	}
+
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		ctx := context.Background()
+
+		db, err := connect(ctx, dsn)
+		if err != nil {
+			t.Fatalf("could not connect to the database: %s", err)
+		}
+		t.Cleanup(func() {
+			db.Close()
+		})
+
+		st, err := open(ctx, db, storage.Timeouts{Query: 5 * time.Second, Bulk: time.Minute})
+		if err != nil {
+			t.Fatalf("could not open storage: %s", err)
+		}
+
+		if _, err = db.ExecContext(ctx, "TRUNCATE users, games, games_quarantine, game_attempts, game_attempts_quarantine CASCADE"); err != nil {
+			t.Fatalf("could not clean the database: %s", err)
+		}
+
+		return st
+	})
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"math"
+	"sort"
+)
+
+// Candidate is a possible target of the word list, suggested by stored games with similar word lists.
+type Candidate struct {
+	Target string
+	// Confidence is from 0 to 1. It is 1, if all similar games have the same word list and target.
+	Confidence float64
+	// Games is amount of stored games, that support the target.
+	Games int
+}
+
+// Similarity is a share of words, common for both lists, among all their words (Jaccard index).
+func Similarity(a, b []string) float64 {
+	set := make(map[string]bool, len(a))
+	for _, word := range a {
+		set[word] = false
+	}
+
+	union := len(set)
+	common := 0
+	for _, word := range b {
+		seen, exists := set[word]
+		if !exists {
+			set[word] = true
+			union++
+			continue
+		}
+		if !seen {
+			set[word] = true
+			common++
+		}
+	}
+
+	if union == 0 {
+		return 0
+	}
+	return float64(common) / float64(union)
+}
+
+// MinOverlap returns amount of common words, that another word list must have at least
+// to be similar to the words by the threshold. Storages use it to skip obviously different games.
+func MinOverlap(words []string, threshold float64) int {
+	unique := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		unique[word] = struct{}{}
+	}
+
+	return max(int(math.Ceil(threshold*float64(len(unique)))), 1)
+}
+
+// RankCandidates groups games, which word lists are similar to the words by the threshold, by their targets, and ranks them
+// by confidence. Each game supports its target with weight of its similarity. Targets, missing in available, are dropped.
+func RankCandidates(words []string, games []Game, threshold float64, available []string) []Candidate {
+	isAvailable := make(map[string]struct{}, len(available))
+	for _, word := range available {
+		isAvailable[word] = struct{}{}
+	}
+
+	weights := make(map[string]float64)
+	best := make(map[string]float64)
+	counts := make(map[string]int)
+	total := 0.0
+	for _, game := range games {
+		if game.Flagged {
+			continue
+		}
+		if _, exists := isAvailable[game.Target]; !exists {
+			continue
+		}
+
+		similarity := Similarity(words, game.Words)
+		if similarity < threshold {
+			continue
+		}
+
+		weights[game.Target] += similarity
+		best[game.Target] = max(best[game.Target], similarity)
+		counts[game.Target]++
+		total += similarity
+	}
+
+	candidates := make([]Candidate, 0, len(weights))
+	for target, weight := range weights {
+		candidates = append(candidates, Candidate{
+			Target: target,
+			// share among other candidates is scaled by the closest word list, so a single distant game is not trusted fully
+			Confidence: weight / total * best[target],
+			Games:      counts[target],
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Confidence != candidates[j].Confidence {
+			return candidates[i].Confidence > candidates[j].Confidence
+		}
+		return candidates[i].Target < candidates[j].Target
+	})
+
+	return candidates
+}
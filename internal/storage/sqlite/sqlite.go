@@ -0,0 +1,705 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"terminal/internal/config"
+	"terminal/internal/storage"
+	"terminal/internal/storage/migrate"
+	"terminal/internal/terminal"
+	"terminal/internal/terminal/dataset"
+	"terminal/migrations"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type Storage struct {
+	db       *sqlx.DB
+	timeouts storage.Timeouts
+}
+
+// New opens SQLite database file and applies migrations, that are not applied yet.
+func New(ctx context.Context, conf config.SQLite, timeouts storage.Timeouts) (*Storage, error) {
+	db, err := connect(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite serializes writing transactions itself, so no lock is needed
+	runner, err := migrate.New(db, migrations.SQLite, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = runner.Up(ctx); err != nil {
+		return nil, fmt.Errorf("could not apply migrations: %w", err)
+	}
+
+	return &Storage{
+		db:       db,
+		timeouts: timeouts,
+	}, nil
+}
+
+// Migrator opens the database without applying migrations, so they could be managed manually.
+func Migrator(ctx context.Context, conf config.SQLite) (*migrate.Runner, error) {
+	db, err := connect(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.New(db, migrations.SQLite, nil)
+}
+
+func connect(ctx context.Context, conf config.SQLite) (*sqlx.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_time_format=sqlite", conf.Path)
+
+	db, err := sqlx.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = db.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// words is a word list, stored as JSON array, since SQLite has no arrays.
+type words []string
+
+func (w words) Value() (driver.Value, error) {
+	if w == nil {
+		w = words{}
+	}
+	data, err := json.Marshal([]string(w))
+	return string(data), err
+}
+
+func (w *words) Scan(src any) error {
+	switch src := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(src), w)
+	case []byte:
+		return json.Unmarshal(src, w)
+	}
+	return fmt.Errorf("sqlite: could not scan %T into word list", src)
+}
+
+// ids returns JSON array of IDs, so they could be passed to `IN (SELECT value FROM json_each(?))`.
+func ids(values []string) string {
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+func (s *Storage) SaveUser(ctx context.Context, telegramID int64, username string, firstname string, lastname string) (*storage.User, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	query := "INSERT INTO users (telegram_id, username, firstname, lastname, created_at) VALUES (?, ?, ?, ?, ?) RETURNING id, telegram_id, username, firstname, lastname, is_admin, created_at"
+	row := s.db.QueryRowContext(ctx, query, telegramID, username, firstname, lastname, time.Now().UTC())
+
+	var user storage.User
+	err := row.Scan(&user.ID, &user.TelegramID, &user.Username, &user.FirstName, &user.LastName, &user.IsAdmin, &user.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, storage.ErrUserAlreadyExists
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (s *Storage) GetUserByTelegramID(ctx context.Context, telegramID int64) (*storage.User, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	query := "SELECT id, telegram_id, username, firstname, lastname, is_admin, created_at FROM users WHERE telegram_id = ?"
+
+	var user storage.User
+	err := s.db.QueryRowContext(ctx, query, telegramID).Scan(&user.ID, &user.TelegramID, &user.Username, &user.FirstName, &user.LastName, &user.IsAdmin, &user.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (s *Storage) SaveGame(ctx context.Context, telegramID int64, list []string, target string, attemptsAmount int, attempts []storage.Attempt) (*storage.Game, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO games (telegram_id, words, target, attempts_amount, words_hash, created_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING id, telegram_id, target, attempts_amount, words_hash, flagged, created_at"
+	wordsHash := terminal.ComputeWordsHash(copyWords(list))
+
+	var game storage.Game
+	err = tx.QueryRowContext(ctx, query, telegramID, words(list), target, attemptsAmount, wordsHash, time.Now().UTC()).
+		Scan(&game.ID, &game.TelegramID, &game.Target, &game.AttemptsAmount, &game.WordsHash, &game.Flagged, &game.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	game.Words = list
+
+	query = "INSERT INTO game_attempts (game_id, number, word, guessed_letters) VALUES (?, ?, ?, ?)"
+	for _, attempt := range attempts {
+		_, err = tx.ExecContext(ctx, query, game.ID, attempt.Number, attempt.Word, attempt.GuessedLetters)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &game, nil
+}
+
+func (s *Storage) TryFindAnswer(ctx context.Context, list []string) (string, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	wordsHash := terminal.ComputeWordsHash(copyWords(list))
+
+	query := "SELECT target FROM games WHERE words_hash = ? AND NOT flagged"
+
+	var target string
+	err := s.db.QueryRowContext(ctx, query, wordsHash).Scan(&target)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", storage.ErrGameNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return target, nil
+}
+
+func (s *Storage) FindCandidates(ctx context.Context, list []string, available []string, threshold float64) ([]storage.Candidate, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	query := `
+        SELECT id, telegram_id, words, target, attempts_amount, words_hash, flagged, created_at FROM games
+        WHERE NOT flagged AND target IN (SELECT value FROM json_each(?))
+            AND (SELECT count(DISTINCT value) FROM json_each(games.words) WHERE value IN (SELECT value FROM json_each(?))) >= ?`
+
+	rows, err := s.db.QueryContext(ctx, query, words(available), words(list), storage.MinOverlap(list, threshold))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var games []storage.Game
+
+	for rows.Next() {
+		var game storage.Game
+		var stored words
+		err = rows.Scan(&game.ID, &game.TelegramID, &stored, &game.Target, &game.AttemptsAmount, &game.WordsHash, &game.Flagged, &game.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		game.Words = []string(stored)
+
+		games = append(games, game)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return storage.RankCandidates(list, games, threshold, available), nil
+}
+
+func (s *Storage) GetDataset(ctx context.Context) (*dataset.Dataset, error) {
+	games := make([]dataset.Game, 0)
+	err := s.StreamDataset(ctx, dataset.Filter{}, func(game dataset.Game) error {
+		games = append(games, game)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var data dataset.Dataset
+
+	data.Games = games
+	data.TotalGames = len(games)
+
+	return &data, nil
+}
+
+func (s *Storage) StreamDataset(ctx context.Context, filter dataset.Filter, fn func(dataset.Game) error) error {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Bulk)
+	defer cancel()
+
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "games.created_at >= ?")
+		args = append(args, filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "games.created_at < ?")
+		args = append(args, filter.To.UTC())
+	}
+	if filter.TelegramID != 0 {
+		conditions = append(conditions, "games.telegram_id = ?")
+		args = append(args, filter.TelegramID)
+	}
+	if filter.WordLength != 0 {
+		conditions = append(conditions, "length(json_extract(games.words, '$[0]')) = ?")
+		args = append(args, filter.WordLength)
+	}
+	if filter.MinWords != 0 {
+		conditions = append(conditions, "json_array_length(games.words) >= ?")
+		args = append(args, filter.MinWords)
+	}
+
+	query := `
+        SELECT games.words, games.target, games.attempts_amount, games.words_hash, games.created_at, users.username, users.telegram_id,
+            (SELECT json_group_array(json_object('word', a.word, 'guessed_letters', a.guessed_letters) ORDER BY a.number) FROM game_attempts a WHERE a.game_id = games.id)
+        FROM games JOIN users ON games.telegram_id = users.telegram_id`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY games.created_at DESC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var game dataset.Game
+		var list words
+		var attempts string
+		err = rows.Scan(&list, &game.Target, &game.AttemptsAmount, &game.WordsHash, &game.CreatedAt, &game.User.Username, &game.User.TelegramID, &attempts)
+		if err != nil {
+			return err
+		}
+		game.Words = []string(list)
+
+		if attempts != "[]" {
+			if err = json.Unmarshal([]byte(attempts), &game.Attempts); err != nil {
+				return err
+			}
+		}
+
+		if err = fn(game); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *Storage) GetAllGames(ctx context.Context) ([]storage.Game, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Bulk)
+	defer cancel()
+
+	query := "SELECT id, telegram_id, words, target, attempts_amount, words_hash, flagged, created_at FROM games"
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var games []storage.Game
+
+	for rows.Next() {
+		var game storage.Game
+		var list words
+		err = rows.Scan(&game.ID, &game.TelegramID, &list, &game.Target, &game.AttemptsAmount, &game.WordsHash, &game.Flagged, &game.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		game.Words = []string(list)
+
+		games = append(games, game)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return games, nil
+}
+
+func (s *Storage) GetDailyReport(ctx context.Context, date time.Time) (*storage.DailyReport, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location()).UTC()
+	end := start.AddDate(0, 0, 1)
+
+	query := `
+        SELECT u.username, COUNT(g.id) AS played_today
+        FROM users u
+        JOIN games g ON u.telegram_id = g.telegram_id
+        WHERE g.created_at >= ? AND g.created_at < ?
+        GROUP BY u.username
+        ORDER BY played_today DESC`
+
+	rows, err := s.db.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var userStats []storage.UserStat
+
+	for rows.Next() {
+		var stat storage.UserStat
+		err = rows.Scan(&stat.Username, &stat.GamesPlayed)
+		if err != nil {
+			return nil, err
+		}
+		userStats = append(userStats, stat)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = `
+        SELECT username
+        FROM users
+        WHERE created_at >= ? AND created_at < ?`
+
+	rows, err = s.db.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var usersJoined []string
+
+	for rows.Next() {
+		var userJoined string
+		err := rows.Scan(&userJoined)
+		if err != nil {
+			return nil, err
+		}
+		usersJoined = append(usersJoined, userJoined)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var report storage.DailyReport
+	report.Stats = userStats
+	report.JoinedUsers = usersJoined
+
+	return &report, nil
+}
+
+func (s *Storage) GetGamesToUserStatistics(ctx context.Context) ([]storage.UserStat, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	query := "SELECT u.username, COUNT(g.id) AS games_played FROM users u LEFT JOIN games g ON u.telegram_id = g.telegram_id GROUP BY u.username ORDER BY games_played DESC"
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	stats := make([]storage.UserStat, 0)
+	for rows.Next() {
+		var stat storage.UserStat
+		err = rows.Scan(&stat.Username, &stat.GamesPlayed)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (s *Storage) GetUsersCount(ctx context.Context) (int, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	query := "SELECT COUNT(*) FROM users"
+
+	var usersAmount int
+	err := s.db.QueryRowContext(ctx, query).Scan(&usersAmount)
+	if err != nil {
+		return 0, err
+	}
+
+	return usersAmount, nil
+}
+
+func (s *Storage) GetWordStats(ctx context.Context) ([]storage.WordStat, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Bulk)
+	defer cancel()
+
+	query := `
+        SELECT w.value AS word, COUNT(*) AS appearances, SUM(w.value = g.target) AS targets
+        FROM games g, json_each(g.words) AS w
+        WHERE NOT g.flagged
+        GROUP BY w.value`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	stats := make([]storage.WordStat, 0)
+	for rows.Next() {
+		var stat storage.WordStat
+		err = rows.Scan(&stat.Word, &stat.Appearances, &stat.Targets)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (s *Storage) FlagGames(ctx context.Context, gameIDs []string) error {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	query := "UPDATE games SET flagged = true WHERE id IN (SELECT value FROM json_each(?))"
+
+	_, err := s.db.ExecContext(ctx, query, ids(gameIDs))
+	return err
+}
+
+func (s *Storage) QuarantineGames(ctx context.Context, gameIDs []string) error {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT OR IGNORE INTO games_quarantine (id, telegram_id, words, target, attempts_amount, words_hash, created_at)
+        SELECT id, telegram_id, words, target, attempts_amount, words_hash, created_at FROM games WHERE id IN (SELECT value FROM json_each(?))`
+
+	_, err = tx.ExecContext(ctx, query, ids(gameIDs))
+	if err != nil {
+		return err
+	}
+
+	// attempts are moved too, since they are deleted with the game, so the quarantine could be undone
+	query = `
+        INSERT OR IGNORE INTO game_attempts_quarantine (game_id, number, word, guessed_letters)
+        SELECT game_id, number, word, guessed_letters FROM game_attempts WHERE game_id IN (SELECT value FROM json_each(?))`
+
+	_, err = tx.ExecContext(ctx, query, ids(gameIDs))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM games WHERE id IN (SELECT value FROM json_each(?))", ids(gameIDs))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Storage) DeleteGame(ctx context.Context, id string) error {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	// attempts are deleted by the foreign key cascade
+	result, err := s.db.ExecContext(ctx, "DELETE FROM games WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return storage.ErrGameNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) GetRandomGame(ctx context.Context) (*storage.Game, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	query := `
+        SELECT id, telegram_id, words, target, attempts_amount, words_hash, flagged, created_at FROM games
+        WHERE NOT flagged AND EXISTS (SELECT 1 FROM json_each(games.words) WHERE value = games.target)
+        ORDER BY random() LIMIT 1`
+
+	var game storage.Game
+	var list words
+	err := s.db.QueryRowContext(ctx, query).Scan(&game.ID, &game.TelegramID, &list, &game.Target, &game.AttemptsAmount, &game.WordsHash, &game.Flagged, &game.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrGameNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	game.Words = []string(list)
+
+	return &game, nil
+}
+
+func (s *Storage) GetAttempts(ctx context.Context, gameID string) ([]storage.Attempt, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Query)
+	defer cancel()
+
+	query := "SELECT game_id, number, word, guessed_letters FROM game_attempts WHERE game_id = ? ORDER BY number"
+
+	attempts := make([]storage.Attempt, 0)
+	err := s.db.SelectContext(ctx, &attempts, query, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+func (s *Storage) ImportGames(ctx context.Context, games []dataset.Game) (*storage.ImportSummary, error) {
+	ctx, cancel := storage.WithTimeout(ctx, s.timeouts.Bulk)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var summary storage.ImportSummary
+
+	users := make(map[int64]bool) // value: user exists
+	for _, game := range games {
+		exists, checked := users[game.User.TelegramID]
+		if !checked {
+			result, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO users (telegram_id, username, created_at) VALUES (?, ?, ?)", game.User.TelegramID, game.User.Username, time.Now().UTC())
+			if err != nil {
+				return nil, err
+			}
+			if added, _ := result.RowsAffected(); added != 0 {
+				summary.UsersAdded++
+			}
+
+			err = tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE telegram_id = ?)", game.User.TelegramID).Scan(&exists)
+			if err != nil {
+				return nil, err
+			}
+			users[game.User.TelegramID] = exists
+		}
+
+		if !exists { // username is taken by another user
+			summary.Conflicting = append(summary.Conflicting, game)
+			continue
+		}
+
+		wordsHash := terminal.ComputeWordsHash(copyWords(game.Words))
+		createdAt := game.CreatedAt.UTC()
+
+		var duplicate bool
+		query := "SELECT EXISTS (SELECT 1 FROM games WHERE words_hash = ? AND target = ? AND telegram_id = ? AND created_at = ?)"
+		err = tx.QueryRowContext(ctx, query, wordsHash, game.Target, game.User.TelegramID, createdAt).Scan(&duplicate)
+		if err != nil {
+			return nil, err
+		}
+		if duplicate {
+			summary.Skipped++
+			continue
+		}
+
+		var conflicting bool
+		query = "SELECT EXISTS (SELECT 1 FROM games WHERE words_hash = ? AND target <> ? AND NOT flagged)"
+		err = tx.QueryRowContext(ctx, query, wordsHash, game.Target).Scan(&conflicting)
+		if err != nil {
+			return nil, err
+		}
+		if conflicting {
+			summary.Conflicting = append(summary.Conflicting, game)
+			continue
+		}
+
+		var id string
+		query = "INSERT INTO games (telegram_id, words, target, attempts_amount, words_hash, created_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING id"
+		err = tx.QueryRowContext(ctx, query, game.User.TelegramID, words(game.Words), game.Target, game.AttemptsAmount, wordsHash, createdAt).Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, attempt := range game.Attempts {
+			query = "INSERT INTO game_attempts (game_id, number, word, guessed_letters) VALUES (?, ?, ?, ?)"
+			_, err = tx.ExecContext(ctx, query, id, i+1, attempt.Word, attempt.GuessedLetters)
+			if err != nil {
+				return nil, err
+			}
+		}
+		summary.Added++
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// copyWords returns a copy of the word list, so it could be sorted for hashing without changing the caller's list.
+func copyWords(words []string) []string {
+	copied := make([]string, len(words))
+	copy(copied, words)
+	return copied
+}
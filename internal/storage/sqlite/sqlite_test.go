@@ -0,0 +1,24 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"terminal/internal/config"
+	"terminal/internal/storage"
+	"terminal/internal/storage/sqlite"
+	"terminal/internal/storage/storagetest"
+	"testing"
+	"time"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		conf := config.SQLite{Path: filepath.Join(t.TempDir(), "terminal.db")}
+
+		st, err := sqlite.New(context.Background(), conf, storage.Timeouts{Query: 5 * time.Second, Bulk: time.Minute})
+		if err != nil {
+			t.Fatalf("could not open storage: %s", err)
+		}
+		return st
+	})
+}
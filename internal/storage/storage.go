@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"terminal/internal/terminal/dataset"
 	"time"
 
@@ -12,18 +13,52 @@ import (
 var (
 	ErrUserNotFound      = errors.New("0xterminal.storage: user not found")
 	ErrUserAlreadyExists = errors.New("0xterminal.storage: user already exists")
+	ErrGameNotFound      = errors.New("0xterminal.storage: game not found")
 )
 
 type Storage interface {
-	SaveUser(telegramID int64, username string, firstname string, lastname string) (*User, error)
-	GetUserByTelegramID(telegramID int64) (*User, error)
-	SaveGame(telegramID int64, words []string, target string, attemptsAmount int) (*Game, error)
-	TryFindAnswer(words []string) (string, error)
-	GetDataset() (*dataset.Dataset, error)
-	GetAllGames() ([]Game, error)
-	GetDailyReport(date time.Time) (*DailyReport, error)
-	GetGamesToUserStatistics() ([]UserStat, error)
-	GetUsersCount() (int, error)
+	SaveUser(ctx context.Context, telegramID int64, username string, firstname string, lastname string) (*User, error)
+	GetUserByTelegramID(ctx context.Context, telegramID int64) (*User, error)
+	// SaveGame stores the game with its attempts at once, so the game is never stored without them.
+	// Attempts are stored by their numbers, game IDs of attempts are ignored.
+	SaveGame(ctx context.Context, telegramID int64, words []string, target string, attemptsAmount int, attempts []Attempt) (*Game, error)
+	TryFindAnswer(ctx context.Context, words []string) (string, error)
+	// FindCandidates ranks targets of unflagged games, which word lists are similar to the words by the threshold,
+	// keeping only targets from available. See RankCandidates.
+	FindCandidates(ctx context.Context, words []string, available []string, threshold float64) ([]Candidate, error)
+	GetDataset(ctx context.Context) (*dataset.Dataset, error)
+	// StreamDataset calls fn for each game, matching the filter, newest first, without loading all of them in memory.
+	StreamDataset(ctx context.Context, filter dataset.Filter, fn func(dataset.Game) error) error
+	GetAllGames(ctx context.Context) ([]Game, error)
+	GetDailyReport(ctx context.Context, date time.Time) (*DailyReport, error)
+	GetGamesToUserStatistics(ctx context.Context) ([]UserStat, error)
+	GetUsersCount(ctx context.Context) (int, error)
+	GetWordStats(ctx context.Context) ([]WordStat, error)
+	FlagGames(ctx context.Context, ids []string) error
+	QuarantineGames(ctx context.Context, ids []string) error
+	// DeleteGame removes the game with its attempts, without moving it to quarantine, e.g. when its user
+	// undid the last attempt. It returns ErrGameNotFound, if there is no such game.
+	DeleteGame(ctx context.Context, id string) error
+	GetRandomGame(ctx context.Context) (*Game, error)
+	GetAttempts(ctx context.Context, gameID string) ([]Attempt, error)
+	// ImportGames merges games from the dataset into storage, creating users they refer to.
+	ImportGames(ctx context.Context, games []dataset.Game) (*ImportSummary, error)
+}
+
+// Timeouts limit storage calls, in addition to deadlines of their contexts.
+type Timeouts struct {
+	// Query limits a single query.
+	Query time.Duration
+	// Bulk limits calls, that read or write all games: dataset streaming, audit, import and word stats.
+	Bulk time.Duration
+}
+
+// WithTimeout limits ctx by the timeout. Zero timeout leaves ctx deadline as is.
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
 }
 
 const (
@@ -48,9 +83,18 @@ type Game struct {
 	Target         string    `db:"target"`
 	AttemptsAmount int       `db:"attempts_amount"`
 	WordsHash      string    `db:"words_hash"`
+	Flagged        bool      `db:"flagged"`
 	CreatedAt      time.Time `db:"created_at"`
 }
 
+// Attempt is a word, submitted in the game, with amount of guessed letters. Number is its position in the game, starting from 1.
+type Attempt struct {
+	GameID         string `db:"game_id"`
+	Number         int    `db:"number"`
+	Word           string `db:"word"`
+	GuessedLetters int    `db:"guessed_letters"`
+}
+
 type DailyReport struct {
 	Stats       []UserStat
 	JoinedUsers []string
@@ -60,3 +104,21 @@ type UserStat struct {
 	Username    string
 	GamesPlayed int
 }
+
+// WordStat is amount of games, where the word appeared in the list, and where it was the target.
+type WordStat struct {
+	Word        string `db:"word"`
+	Appearances int    `db:"appearances"`
+	Targets     int    `db:"targets"`
+}
+
+// ImportSummary describes outcome of importing games.
+type ImportSummary struct {
+	UsersAdded int
+	Added      int
+	// Skipped is amount of games, that are already stored, with the same word list, target, user and time.
+	Skipped int
+	// Conflicting are games, that were not imported, because stored games have a different target for the word list,
+	// or their user could not be created.
+	Conflicting []dataset.Game
+}
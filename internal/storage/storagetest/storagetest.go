@@ -0,0 +1,606 @@
+// Package storagetest is a conformance suite for storage.Storage implementations,
+// so all backends behave the same way, the handler expects them to.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"terminal/internal/storage"
+	"terminal/internal/terminal/dataset"
+	"testing"
+	"time"
+)
+
+// Run runs the conformance suite. Each call of newStorage must return an empty storage.
+//
+//	func TestStorage(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T) storage.Storage {
+//			return memory.New()
+//		})
+//	}
+func Run(t *testing.T, newStorage func(t *testing.T) storage.Storage) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, st storage.Storage)
+	}{
+		{"Users", testUsers},
+		{"Games", testGames},
+		{"Candidates", testCandidates},
+		{"Dataset", testDataset},
+		{"DailyReport", testDailyReport},
+		{"Statistics", testStatistics},
+		{"WordStats", testWordStats},
+		{"FlagAndQuarantine", testFlagAndQuarantine},
+		{"DeleteGame", testDeleteGame},
+		{"RandomGame", testRandomGame},
+		{"Attempts", testAttempts},
+		{"Import", testImport},
+		{"Concurrency", testConcurrency},
+		{"Canceled", testCanceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStorage(t))
+		})
+	}
+}
+
+var words = []string{"stone", "shore", "stove", "score", "spoke", "smoke"}
+
+func mustSaveUser(t *testing.T, st storage.Storage, telegramID int64, username string) *storage.User {
+	t.Helper()
+
+	ctx := context.Background()
+
+	user, err := st.SaveUser(ctx, telegramID, username, "", "")
+	if err != nil {
+		t.Fatalf("SaveUser(%d, %q): %s", telegramID, username, err)
+	}
+	return user
+}
+
+func mustSaveGame(t *testing.T, st storage.Storage, telegramID int64, words []string, target string) *storage.Game {
+	t.Helper()
+
+	ctx := context.Background()
+
+	game, err := st.SaveGame(ctx, telegramID, words, target, 2, nil)
+	if err != nil {
+		t.Fatalf("SaveGame(%d, %v, %q): %s", telegramID, words, target, err)
+	}
+	return game
+}
+
+func testUsers(t *testing.T, st storage.Storage) {
+	ctx := context.Background()
+
+	_, err := st.GetUserByTelegramID(ctx, 1)
+	if !errors.Is(err, storage.ErrUserNotFound) {
+		t.Fatalf("GetUserByTelegramID() of unknown user: got %v, want %v", err, storage.ErrUserNotFound)
+	}
+
+	saved := mustSaveUser(t, st, 1, "alice")
+	if saved.ID == "" || saved.TelegramID != 1 || saved.Username != "alice" || saved.IsAdmin || saved.CreatedAt.IsZero() {
+		t.Fatalf("SaveUser() returned unexpected user: %+v", saved)
+	}
+
+	got, err := st.GetUserByTelegramID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetUserByTelegramID(): %s", err)
+	}
+	if got.ID != saved.ID || got.Username != saved.Username {
+		t.Fatalf("GetUserByTelegramID(): got %+v, want %+v", got, saved)
+	}
+
+	if _, err = st.SaveUser(ctx, 1, "bob", "", ""); !errors.Is(err, storage.ErrUserAlreadyExists) {
+		t.Fatalf("SaveUser() with taken telegram ID: got %v, want %v", err, storage.ErrUserAlreadyExists)
+	}
+	if _, err = st.SaveUser(ctx, 2, "alice", "", ""); !errors.Is(err, storage.ErrUserAlreadyExists) {
+		t.Fatalf("SaveUser() with taken username: got %v, want %v", err, storage.ErrUserAlreadyExists)
+	}
+
+	count, err := st.GetUsersCount(ctx)
+	if err != nil {
+		t.Fatalf("GetUsersCount(): %s", err)
+	}
+	if count != 1 {
+		t.Fatalf("GetUsersCount(): got %d, want 1", count)
+	}
+}
+
+func testGames(t *testing.T, st storage.Storage) {
+	ctx := context.Background()
+
+	mustSaveUser(t, st, 1, "alice")
+
+	if _, err := st.TryFindAnswer(ctx, words); !errors.Is(err, storage.ErrGameNotFound) {
+		t.Fatalf("TryFindAnswer() of unknown list: got %v, want %v", err, storage.ErrGameNotFound)
+	}
+
+	list := []string{"stone", "shore", "stove", "score", "spoke", "smoke"}
+	game := mustSaveGame(t, st, 1, list, "stove")
+	if game.ID == "" || game.TelegramID != 1 || game.Target != "stove" || game.WordsHash == "" || game.Flagged {
+		t.Fatalf("SaveGame() returned unexpected game: %+v", game)
+	}
+	if !slices.Equal(list, words) {
+		t.Fatalf("SaveGame() changed order of the words: got %v, want %v", list, words)
+	}
+
+	// word list hash doesn't depend on words order
+	shuffled := []string{"smoke", "spoke", "score", "stove", "shore", "stone"}
+	target, err := st.TryFindAnswer(ctx, shuffled)
+	if err != nil {
+		t.Fatalf("TryFindAnswer(): %s", err)
+	}
+	if target != "stove" {
+		t.Fatalf("TryFindAnswer(): got %q, want %q", target, "stove")
+	}
+	if !slices.Equal(shuffled, []string{"smoke", "spoke", "score", "stove", "shore", "stone"}) {
+		t.Fatalf("TryFindAnswer() changed order of the words: got %v", shuffled)
+	}
+
+	games, err := st.GetAllGames(ctx)
+	if err != nil {
+		t.Fatalf("GetAllGames(): %s", err)
+	}
+	if len(games) != 1 || games[0].ID != game.ID || len(games[0].Words) != len(words) {
+		t.Fatalf("GetAllGames(): got %+v, want only %+v", games, game)
+	}
+}
+
+func testCandidates(t *testing.T, st storage.Storage) {
+	ctx := context.Background()
+
+	mustSaveUser(t, st, 1, "alice")
+
+	list := []string{"stone", "shore", "stove", "score", "spoke", "smoke"}
+	mustSaveGame(t, st, 1, []string{"stone", "shore", "stove", "score", "spoke", "smoke"}, "stove")
+	// one word was misread, similarity is 5/7
+	mustSaveGame(t, st, 1, []string{"stone", "shore", "stove", "score", "spoke", "spore"}, "stove")
+	mustSaveGame(t, st, 1, []string{"crane", "plane", "brine", "drone", "prone", "crone"}, "crane")
+	flagged := mustSaveGame(t, st, 1, []string{"stone", "shore", "stove", "score", "spoke", "smoke"}, "shore")
+	if err := st.FlagGames(ctx, []string{flagged.ID}); err != nil {
+		t.Fatalf("FlagGames(): %s", err)
+	}
+
+	candidates, err := st.FindCandidates(ctx, list, list, 0.7)
+	if err != nil {
+		t.Fatalf("FindCandidates(): %s", err)
+	}
+	if len(candidates) != 1 || candidates[0].Target != "stove" || candidates[0].Games != 2 || candidates[0].Confidence != 1 {
+		t.Fatalf("FindCandidates(): got %+v, want single stove candidate with 2 games and confidence 1", candidates)
+	}
+
+	candidates, err = st.FindCandidates(ctx, list, list, 0.9)
+	if err != nil {
+		t.Fatalf("FindCandidates(): %s", err)
+	}
+	if len(candidates) != 1 || candidates[0].Games != 1 {
+		t.Fatalf("FindCandidates() above similarity of misread list: got %+v, want single game", candidates)
+	}
+
+	candidates, err = st.FindCandidates(ctx, list, []string{"stone", "shore"}, 0.7)
+	if err != nil {
+		t.Fatalf("FindCandidates(): %s", err)
+	}
+	if len(candidates) != 0 {
+		t.Fatalf("FindCandidates() must skip targets, that are not available: got %+v", candidates)
+	}
+
+	mustSaveGame(t, st, 1, []string{"stone", "shore", "stove", "score", "spoke", "smoke"}, "score")
+
+	candidates, err = st.FindCandidates(ctx, list, list, 0.7)
+	if err != nil {
+		t.Fatalf("FindCandidates(): %s", err)
+	}
+	if len(candidates) != 2 || candidates[0].Target != "stove" || candidates[1].Target != "score" {
+		t.Fatalf("FindCandidates(): got %+v, want stove ranked above score", candidates)
+	}
+	if candidates[0].Confidence <= candidates[1].Confidence || candidates[0].Confidence >= 1 {
+		t.Fatalf("FindCandidates(): unexpected confidence of %+v", candidates)
+	}
+}
+
+func testDataset(t *testing.T, st storage.Storage) {
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	_, err := st.ImportGames(ctx, []dataset.Game{
+		{
+			Words:          words,
+			Target:         "stone",
+			AttemptsAmount: 1,
+			User:           dataset.User{TelegramID: 1, Username: "alice"},
+			CreatedAt:      now.Add(-48 * time.Hour),
+		},
+		{
+			Words:          []string{"pack", "pick", "puck", "peck", "pock", "pink", "punk"},
+			Target:         "pink",
+			AttemptsAmount: 2,
+			User:           dataset.User{TelegramID: 2, Username: "bob"},
+			CreatedAt:      now.Add(-time.Hour),
+			Attempts:       []dataset.Attempt{{Word: "pack", GuessedLetters: 1}, {Word: "pink", GuessedLetters: 4}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ImportGames(): %s", err)
+	}
+
+	data, err := st.GetDataset(ctx)
+	if err != nil {
+		t.Fatalf("GetDataset(): %s", err)
+	}
+	if data.TotalGames != 2 || len(data.Games) != 2 {
+		t.Fatalf("GetDataset(): got %d games, want 2", len(data.Games))
+	}
+	if data.Games[0].Target != "pink" || data.Games[1].Target != "stone" {
+		t.Fatalf("GetDataset(): games are not ordered from newest to oldest: %+v", data.Games)
+	}
+	if data.Games[0].User.Username != "bob" || data.Games[0].User.TelegramID != 2 {
+		t.Fatalf("GetDataset(): unexpected user of the game: %+v", data.Games[0].User)
+	}
+	if len(data.Games[0].Attempts) != 2 || data.Games[0].Attempts[1].Word != "pink" {
+		t.Fatalf("GetDataset(): unexpected attempts of the game: %+v", data.Games[0].Attempts)
+	}
+
+	filters := []struct {
+		name   string
+		filter dataset.Filter
+		want   []string // targets
+	}{
+		{"From", dataset.Filter{From: now.Add(-24 * time.Hour)}, []string{"pink"}},
+		{"To", dataset.Filter{To: now.Add(-24 * time.Hour)}, []string{"stone"}},
+		{"TelegramID", dataset.Filter{TelegramID: 1}, []string{"stone"}},
+		{"WordLength", dataset.Filter{WordLength: 4}, []string{"pink"}},
+		{"MinWords", dataset.Filter{MinWords: 7}, []string{"pink"}},
+		{"Nothing", dataset.Filter{TelegramID: 1, WordLength: 4}, nil},
+	}
+	for _, tt := range filters {
+		var got []string
+		err = st.StreamDataset(ctx, tt.filter, func(game dataset.Game) error {
+			got = append(got, game.Target)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StreamDataset(%s): %s", tt.name, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Fatalf("StreamDataset(%s): got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = st.StreamDataset(ctx, dataset.Filter{}, func(game dataset.Game) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("StreamDataset() must stop on the first error: got %v after %d calls", err, calls)
+	}
+}
+
+func testDailyReport(t *testing.T, st storage.Storage) {
+	ctx := context.Background()
+
+	mustSaveUser(t, st, 1, "alice")
+	mustSaveUser(t, st, 2, "bob")
+	mustSaveGame(t, st, 1, words, "stone")
+	mustSaveGame(t, st, 1, words, "stone")
+	mustSaveGame(t, st, 2, words, "stone")
+
+	report, err := st.GetDailyReport(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("GetDailyReport(): %s", err)
+	}
+	if len(report.Stats) != 2 || report.Stats[0].Username != "alice" || report.Stats[0].GamesPlayed != 2 || report.Stats[1].GamesPlayed != 1 {
+		t.Fatalf("GetDailyReport(): unexpected stats %+v", report.Stats)
+	}
+	if len(report.JoinedUsers) != 2 {
+		t.Fatalf("GetDailyReport(): got joined users %v, want alice and bob", report.JoinedUsers)
+	}
+
+	report, err = st.GetDailyReport(ctx, time.Now().AddDate(0, 0, -7))
+	if err != nil {
+		t.Fatalf("GetDailyReport(): %s", err)
+	}
+	if len(report.Stats) != 0 || len(report.JoinedUsers) != 0 {
+		t.Fatalf("GetDailyReport() of a week ago: got %+v, want empty report", report)
+	}
+}
+
+func testStatistics(t *testing.T, st storage.Storage) {
+	ctx := context.Background()
+
+	mustSaveUser(t, st, 1, "alice")
+	mustSaveUser(t, st, 2, "bob")
+	mustSaveGame(t, st, 2, words, "stone")
+
+	stats, err := st.GetGamesToUserStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetGamesToUserStatistics(): %s", err)
+	}
+
+	want := []storage.UserStat{{Username: "bob", GamesPlayed: 1}, {Username: "alice", GamesPlayed: 0}}
+	if len(stats) != len(want) || stats[0] != want[0] || stats[1] != want[1] {
+		t.Fatalf("GetGamesToUserStatistics(): got %+v, want %+v", stats, want)
+	}
+}
+
+func testWordStats(t *testing.T, st storage.Storage) {
+	ctx := context.Background()
+
+	mustSaveUser(t, st, 1, "alice")
+	mustSaveGame(t, st, 1, words, "stone")
+	mustSaveGame(t, st, 1, words, "shore")
+	flagged := mustSaveGame(t, st, 1, words, "smoke")
+
+	if err := st.FlagGames(ctx, []string{flagged.ID}); err != nil {
+		t.Fatalf("FlagGames(): %s", err)
+	}
+
+	stats, err := st.GetWordStats(ctx)
+	if err != nil {
+		t.Fatalf("GetWordStats(): %s", err)
+	}
+
+	got := make(map[string]storage.WordStat)
+	for _, stat := range stats {
+		got[stat.Word] = stat
+	}
+	if len(got) != len(words) {
+		t.Fatalf("GetWordStats(): got %d words, want %d", len(got), len(words))
+	}
+	for word, want := range map[string]int{"stone": 1, "shore": 1, "smoke": 0, "spoke": 0} {
+		if got[word].Appearances != 2 || got[word].Targets != want {
+			t.Fatalf("GetWordStats(): got %+v for %q, want 2 appearances and %d targets", got[word], word, want)
+		}
+	}
+}
+
+func testFlagAndQuarantine(t *testing.T, st storage.Storage) {
+	ctx := context.Background()
+
+	mustSaveUser(t, st, 1, "alice")
+	flagged := mustSaveGame(t, st, 1, words, "stone")
+	quarantined := mustSaveGame(t, st, 1, []string{"pack", "pick", "puck", "peck", "pock", "pink"}, "pink")
+
+	if err := st.FlagGames(ctx, []string{flagged.ID}); err != nil {
+		t.Fatalf("FlagGames(): %s", err)
+	}
+	if _, err := st.TryFindAnswer(ctx, words); !errors.Is(err, storage.ErrGameNotFound) {
+		t.Fatalf("TryFindAnswer() must skip flagged games: got %v", err)
+	}
+
+	if err := st.QuarantineGames(ctx, []string{quarantined.ID}); err != nil {
+		t.Fatalf("QuarantineGames(): %s", err)
+	}
+
+	games, err := st.GetAllGames(ctx)
+	if err != nil {
+		t.Fatalf("GetAllGames(): %s", err)
+	}
+	if len(games) != 1 || games[0].ID != flagged.ID || !games[0].Flagged {
+		t.Fatalf("GetAllGames(): got %+v, want only flagged game", games)
+	}
+}
+
+func testDeleteGame(t *testing.T, st storage.Storage) {
+	ctx := context.Background()
+
+	mustSaveUser(t, st, 1, "alice")
+	kept := mustSaveGame(t, st, 1, []string{"pack", "pick", "puck", "peck", "pock", "pink"}, "pink")
+	deleted, err := st.SaveGame(ctx, 1, words, "stone", 1, []storage.Attempt{{Number: 1, Word: "stone", GuessedLetters: 5}})
+	if err != nil {
+		t.Fatalf("SaveGame(): %s", err)
+	}
+
+	if err = st.DeleteGame(ctx, deleted.ID); err != nil {
+		t.Fatalf("DeleteGame(): %s", err)
+	}
+	if err = st.DeleteGame(ctx, deleted.ID); !errors.Is(err, storage.ErrGameNotFound) {
+		t.Fatalf("DeleteGame() of deleted game: got %v, want %v", err, storage.ErrGameNotFound)
+	}
+
+	games, err := st.GetAllGames(ctx)
+	if err != nil {
+		t.Fatalf("GetAllGames(): %s", err)
+	}
+	if len(games) != 1 || games[0].ID != kept.ID {
+		t.Fatalf("GetAllGames(): got %+v, want only %+v", games, kept)
+	}
+
+	attempts, err := st.GetAttempts(ctx, deleted.ID)
+	if err != nil {
+		t.Fatalf("GetAttempts(): %s", err)
+	}
+	if len(attempts) != 0 {
+		t.Fatalf("GetAttempts() of deleted game: got %+v", attempts)
+	}
+
+	if _, err = st.TryFindAnswer(ctx, words); !errors.Is(err, storage.ErrGameNotFound) {
+		t.Fatalf("TryFindAnswer() must skip deleted games: got %v", err)
+	}
+}
+
+func testRandomGame(t *testing.T, st storage.Storage) {
+	ctx := context.Background()
+
+	if _, err := st.GetRandomGame(ctx); !errors.Is(err, storage.ErrGameNotFound) {
+		t.Fatalf("GetRandomGame() of empty storage: got %v, want %v", err, storage.ErrGameNotFound)
+	}
+
+	mustSaveUser(t, st, 1, "alice")
+	mustSaveGame(t, st, 1, words, "other") // target is not in the list
+	game := mustSaveGame(t, st, 1, words, "stone")
+
+	got, err := st.GetRandomGame(ctx)
+	if err != nil {
+		t.Fatalf("GetRandomGame(): %s", err)
+	}
+	if got.ID != game.ID {
+		t.Fatalf("GetRandomGame(): got %+v, want %+v", got, game)
+	}
+}
+
+func testAttempts(t *testing.T, st storage.Storage) {
+	ctx := context.Background()
+
+	mustSaveUser(t, st, 1, "alice")
+	empty := mustSaveGame(t, st, 1, words, "stone")
+
+	attempts, err := st.GetAttempts(ctx, empty.ID)
+	if err != nil {
+		t.Fatalf("GetAttempts(): %s", err)
+	}
+	if len(attempts) != 0 {
+		t.Fatalf("GetAttempts() of the game without attempts: got %+v", attempts)
+	}
+
+	// attempts are stored by their numbers, not by their order in the slice
+	game, err := st.SaveGame(ctx, 1, words, "stone", 2, []storage.Attempt{
+		{Number: 2, Word: "stone", GuessedLetters: 5},
+		{Number: 1, Word: "smoke", GuessedLetters: 2},
+	})
+	if err != nil {
+		t.Fatalf("SaveGame(): %s", err)
+	}
+
+	attempts, err = st.GetAttempts(ctx, game.ID)
+	if err != nil {
+		t.Fatalf("GetAttempts(): %s", err)
+	}
+
+	want := []storage.Attempt{
+		{GameID: game.ID, Number: 1, Word: "smoke", GuessedLetters: 2},
+		{GameID: game.ID, Number: 2, Word: "stone", GuessedLetters: 5},
+	}
+	if len(attempts) != len(want) || attempts[0] != want[0] || attempts[1] != want[1] {
+		t.Fatalf("GetAttempts(): got %+v, want %+v", attempts, want)
+	}
+
+	// the game is not stored, if its attempts could not be stored
+	_, err = st.SaveGame(ctx, 1, words, "stone", 1, []storage.Attempt{
+		{Number: 1, Word: "smoke", GuessedLetters: 2},
+		{Number: 1, Word: "stone", GuessedLetters: 5},
+	})
+	if err == nil {
+		t.Fatalf("SaveGame() with duplicate attempt numbers: got no error")
+	}
+
+	games, err := st.GetAllGames(ctx)
+	if err != nil {
+		t.Fatalf("GetAllGames(): %s", err)
+	}
+	if len(games) != 2 {
+		t.Fatalf("GetAllGames(): got %d games, want 2 without the failed one", len(games))
+	}
+}
+
+func testImport(t *testing.T, st storage.Storage) {
+	ctx := context.Background()
+
+	mustSaveUser(t, st, 1, "alice")
+	mustSaveUser(t, st, 2, "bob")
+	mustSaveGame(t, st, 1, words, "stone")
+
+	createdAt := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+	imported := dataset.Game{
+		Words:          []string{"pack", "pick", "puck", "peck", "pock", "pink"},
+		Target:         "pink",
+		AttemptsAmount: 2,
+		User:           dataset.User{TelegramID: 3, Username: "carol"},
+		CreatedAt:      createdAt,
+	}
+
+	summary, err := st.ImportGames(ctx, []dataset.Game{
+		imported,
+		imported, // duplicate
+		{Words: words, Target: "shore", User: dataset.User{TelegramID: 1, Username: "alice"}, CreatedAt: createdAt}, // other target
+		{Words: words, Target: "stone", User: dataset.User{TelegramID: 4, Username: "bob"}, CreatedAt: createdAt},   // taken username
+	})
+	if err != nil {
+		t.Fatalf("ImportGames(): %s", err)
+	}
+	if summary.Added != 1 || summary.Skipped != 1 || len(summary.Conflicting) != 2 || summary.UsersAdded != 1 {
+		t.Fatalf("ImportGames(): unexpected summary %+v", summary)
+	}
+
+	user, err := st.GetUserByTelegramID(ctx, 3)
+	if err != nil {
+		t.Fatalf("GetUserByTelegramID() of imported user: %s", err)
+	}
+	if user.Username != "carol" {
+		t.Fatalf("GetUserByTelegramID() of imported user: got %+v", user)
+	}
+
+	summary, err = st.ImportGames(ctx, []dataset.Game{imported})
+	if err != nil {
+		t.Fatalf("ImportGames(): %s", err)
+	}
+	if summary.Added != 0 || summary.Skipped != 1 {
+		t.Fatalf("ImportGames() of already imported game: unexpected summary %+v", summary)
+	}
+}
+
+func testConcurrency(t *testing.T, st storage.Storage) {
+	ctx := context.Background()
+
+	mustSaveUser(t, st, 1, "alice")
+
+	const n = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*n)
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := st.SaveGame(ctx, 1, words, "stone", 1, nil)
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := st.GetWordStats(ctx)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent call: %s", err)
+		}
+	}
+
+	games, err := st.GetAllGames(ctx)
+	if err != nil {
+		t.Fatalf("GetAllGames(): %s", err)
+	}
+	if len(games) != n {
+		t.Fatalf("GetAllGames(): got %d games, want %d", len(games), n)
+	}
+}
+
+func testCanceled(t *testing.T, st storage.Storage) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := st.GetUsersCount(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetUsersCount() with canceled context: got %v, want %v", err, context.Canceled)
+	}
+	if _, err := st.SaveUser(ctx, 1, "alice", "", ""); !errors.Is(err, context.Canceled) {
+		t.Fatalf("SaveUser() with canceled context: got %v, want %v", err, context.Canceled)
+	}
+
+	err := st.StreamDataset(ctx, dataset.Filter{}, func(game dataset.Game) error {
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("StreamDataset() with canceled context: got %v, want %v", err, context.Canceled)
+	}
+}
@@ -1,14 +1,16 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
 	"terminal/internal/storage"
+	"terminal/internal/terminal"
 	"terminal/internal/terminal/dataset"
 	"terminal/pkg/log/sl"
 	"time"
@@ -16,49 +18,135 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-func (h *Handler) CallbackContinueGame(u tgbotapi.Update) {
+func (h *Handler) CallbackContinueGame(ctx context.Context, u tgbotapi.Update) {
 	author := u.CallbackQuery.From
 	messageID := u.CallbackQuery.Message.MessageID
 
-	game, exists := h.games[author.ID]
+	game, exists := h.activeGame(author.ID)
 	if !exists {
 		h.editMessage(author.ID, messageID, "<b>You have no started games</b>\n\nUse /newgame or button to start new one", GetMarkupNewGame())
 		return
 	}
 
-	h.editMessage(author.ID, messageID, fmt.Sprintf("<b>Pick one of %d words in the list</b>", len(game.AvailableWords())), GetMarkupWords(game.AvailableWords()))
+	h.editMessage(author.ID, messageID, getContentPickWord(game), GetMarkupWords(game))
 }
 
-func (h *Handler) CallbackStartNewGame(u tgbotapi.Update) {
+func (h *Handler) CallbackStartNewGame(ctx context.Context, u tgbotapi.Update) {
 	author := u.CallbackQuery.From
 
-	h.stages[author.ID] = WaitingWordList
-	delete(h.games, author.ID)
+	h.stages.Set(author.ID, WaitingWordList)
+	h.games.Delete(author.ID)
+	h.finished.Delete(author.ID)
 	h.sendTextMessage(author.ID, "Send me list of words in your $TERMINAL game", nil)
 }
 
-func (h *Handler) CallbackWordsList(u tgbotapi.Update) {
+func (h *Handler) CallbackWordsList(ctx context.Context, u tgbotapi.Update) {
 	author := u.CallbackQuery.From
 	messageID := u.CallbackQuery.Message.MessageID
 
-	game, exists := h.games[author.ID]
+	game, exists := h.activeGame(author.ID)
 	if !exists {
 		h.editMessage(author.ID, messageID, "<b>Use /newgame or button to start new game</b>", GetMarkupNewGame())
 		return
 	}
-	h.editMessage(author.ID, messageID, fmt.Sprintf("<b>Pick one of %d words in the list</b>", len(game.AvailableWords())), GetMarkupWords(game.AvailableWords()))
+	h.editMessage(author.ID, messageID, getContentPickWord(game), GetMarkupWords(game))
 }
 
-func (h *Handler) CallbackDataset(u tgbotapi.Update) {
+// DatasetExport is dataset export settings, picked by admin. It is encoded into callback data as
+// `<format>:<period>:<length>:<min words>:<telegram id>:<anonymous>`.
+type DatasetExport struct {
+	Format dataset.Format
+	// Period is amount of days before now to export games from, 0 means all time.
+	Period int
+	// WordLength limits exported games to word lists with such length, 0 means any.
+	WordLength int
+	// MinWords limits exported games to word lists with at least such amount of words, 0 means any.
+	MinWords int
+	// TelegramID limits exported games to games of the user, 0 means all users.
+	TelegramID int64
+	// Anonymous replaces users with pseudonyms and drops users with few games.
+	Anonymous bool
+}
+
+var (
+	DatasetPeriods     = []int{0, 30, 7, 1}
+	DatasetWordLengths = []int{0, 5, 6, 7, 8}
+	DatasetMinWords    = []int{0, 8, 10, 12}
+)
+
+func (e DatasetExport) String() string {
+	anonymous := 0
+	if e.Anonymous {
+		anonymous = 1
+	}
+	return fmt.Sprintf("%s:%d:%d:%d:%d:%d", e.Format, e.Period, e.WordLength, e.MinWords, e.TelegramID, anonymous)
+}
+
+// Filter returns dataset filter for the export settings.
+func (e DatasetExport) Filter(now time.Time) dataset.Filter {
+	filter := dataset.Filter{
+		TelegramID: e.TelegramID,
+		WordLength: e.WordLength,
+		MinWords:   e.MinWords,
+	}
+	if e.Period != 0 {
+		filter.From = now.AddDate(0, 0, -e.Period)
+	}
+	return filter
+}
+
+func parseDatasetExport(data string) (DatasetExport, error) {
+	parts := strings.Split(data, ":")
+	if len(parts) != 6 {
+		return DatasetExport{}, fmt.Errorf("invalid dataset export: %s", data)
+	}
+
+	format, err := dataset.ParseFormat(parts[0])
+	if err != nil {
+		return DatasetExport{}, err
+	}
+
+	period, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return DatasetExport{}, err
+	}
+
+	length, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return DatasetExport{}, err
+	}
+
+	minWords, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return DatasetExport{}, err
+	}
+
+	telegramID, err := strconv.ParseInt(parts[4], 10, 64)
+	if err != nil {
+		return DatasetExport{}, err
+	}
+
+	return DatasetExport{
+		Format:     format,
+		Period:     period,
+		WordLength: length,
+		MinWords:   minWords,
+		TelegramID: telegramID,
+		Anonymous:  parts[5] == "1",
+	}, nil
+}
+
+func (h *Handler) CallbackDataset(ctx context.Context, u tgbotapi.Update) {
 	author := u.CallbackQuery.From
 	messageID := u.CallbackQuery.Message.MessageID
 	log := h.log.With(
 		slog.String("op", "handler.CallbackDataset"),
 		slog.String("username", author.UserName),
 		slog.String("id", strconv.FormatInt(author.ID, 10)),
+		slog.String("query", u.CallbackData()),
 	)
 
-	user, err := h.storage.GetUserByTelegramID(author.ID)
+	user, err := h.storage.GetUserByTelegramID(ctx, author.ID)
 	if err != nil {
 		log.Error("could not get user from database", sl.Err(err))
 		h.editMessage(author.ID, messageID, "<b>Something went wrong... Try again later</b>", nil)
@@ -70,34 +158,127 @@ func (h *Handler) CallbackDataset(u tgbotapi.Update) {
 		return
 	}
 
-	data, err := h.storage.GetDataset()
+	export := DatasetExport{Format: dataset.FormatJSON}
+	if data, found := strings.CutPrefix(u.CallbackData(), "dataset:"); found {
+		export, err = parseDatasetExport(data)
+		if err != nil {
+			log.Error("could not parse dataset export", sl.Err(err))
+			h.editMessage(author.ID, messageID, "<b>Something went wrong... Try again later</b>", GetMarkupBackToAdmin())
+			return
+		}
+	}
+
+	h.editMessage(author.ID, messageID, getContentDatasetExport(export), GetMarkupDataset(export))
+}
+
+// CallbackDatasetUser asks admin to send telegram ID of the user, whose games should be exported.
+func (h *Handler) CallbackDatasetUser(ctx context.Context, u tgbotapi.Update) {
+	author := u.CallbackQuery.From
+	messageID := u.CallbackQuery.Message.MessageID
+	log := h.log.With(
+		slog.String("op", "handler.CallbackDatasetUser"),
+		slog.String("username", author.UserName),
+		slog.String("id", strconv.FormatInt(author.ID, 10)),
+		slog.String("query", u.CallbackData()),
+	)
+
+	user, err := h.storage.GetUserByTelegramID(ctx, author.ID)
 	if err != nil {
-		log.Error("could not build dataset", sl.Err(err))
-		h.editMessage(author.ID, messageID, "<b>Failed to compose dataset</b>", nil)
+		log.Error("could not get user from database", sl.Err(err))
+		h.editMessage(author.ID, messageID, "<b>Something went wrong... Try again later</b>", nil)
 		return
 	}
 
-	path, err := dataset.ExportDatasetToJSON(data)
+	if !user.IsAdmin {
+		h.editMessage(author.ID, messageID, "<b>You are not permitted to use this command</b>", nil)
+		return
+	}
+
+	export, err := parseDatasetExport(strings.TrimPrefix(u.CallbackData(), "dataset-user:"))
 	if err != nil {
-		log.Error("could not export dataset to JSON", sl.Err(err))
-		h.editMessage(author.ID, messageID, "<b>Failed to compose dataset</b>", nil)
+		log.Error("could not parse dataset export", sl.Err(err))
+		h.editMessage(author.ID, messageID, "<b>Something went wrong... Try again later</b>", GetMarkupBackToAdmin())
 		return
 	}
 
-	file, err := os.Open(path)
+	h.datasetExports.Set(author.ID, export)
+	h.stages.Set(author.ID, WaitingDatasetUser)
+	h.editMessage(author.ID, messageID, "<b>Send me telegram ID of the user, whose games should be exported</b>", GetMarkupDataset(export))
+}
+
+func (h *Handler) CallbackDatasetExport(ctx context.Context, u tgbotapi.Update) {
+	author := u.CallbackQuery.From
+	messageID := u.CallbackQuery.Message.MessageID
+	log := h.log.With(
+		slog.String("op", "handler.CallbackDatasetExport"),
+		slog.String("username", author.UserName),
+		slog.String("id", strconv.FormatInt(author.ID, 10)),
+		slog.String("query", u.CallbackData()),
+	)
+
+	user, err := h.storage.GetUserByTelegramID(ctx, author.ID)
 	if err != nil {
-		log.Error("could not open file", sl.Err(err))
-		h.editMessage(author.ID, messageID, "<b>Failed to compose dataset</b>", nil)
+		log.Error("could not get user from database", sl.Err(err))
+		h.editMessage(author.ID, messageID, "<b>Something went wrong... Try again later</b>", nil)
 		return
 	}
-	defer file.Close()
 
-	reader := tgbotapi.FileReader{
-		Name:   "0xterminal-dataset.json",
-		Reader: file,
+	if !user.IsAdmin {
+		h.editMessage(author.ID, messageID, "<b>You are not permitted to use this command</b>", nil)
+		return
 	}
 
-	document := tgbotapi.NewDocument(author.ID, reader)
+	export, err := parseDatasetExport(strings.TrimPrefix(u.CallbackData(), "dataset-export:"))
+	if err != nil {
+		log.Error("could not parse dataset export", sl.Err(err))
+		h.editMessage(author.ID, messageID, "<b>Something went wrong... Try again later</b>", GetMarkupBackToAdmin())
+		return
+	}
+
+	filter := export.Filter(time.Now())
+	stream := func(fn func(dataset.Game) error) error {
+		return h.storage.StreamDataset(ctx, filter, fn)
+	}
+
+	// games are written to the pipe while the document is being uploaded, so the dataset is never kept in memory
+	reader, pipe := io.Pipe()
+
+	writer, err := dataset.NewWriter(pipe, export.Format)
+	if err != nil {
+		log.Error("could not create dataset writer", sl.Err(err))
+		h.editMessage(author.ID, messageID, "<b>Failed to compose dataset</b>", GetMarkupBackToAdmin())
+		return
+	}
+
+	if export.Anonymous {
+		counts, err := dataset.CountGames(stream)
+		if err != nil {
+			log.Error("could not count users games", sl.Err(err))
+			h.editMessage(author.ID, messageID, "<b>Failed to compose dataset</b>", GetMarkupBackToAdmin())
+			return
+		}
+
+		writer, err = dataset.Anonymize(writer, h.anonymization, counts)
+		if err != nil {
+			log.Error("could not anonymise dataset", sl.Err(err))
+			h.editMessage(author.ID, messageID, "<b>Anonymous export is not configured</b>\n\nSet <code>dataset.salt</code> in config", GetMarkupBackToAdmin())
+			return
+		}
+	}
+
+	go func() {
+		err := stream(writer.Write)
+		if err == nil {
+			err = writer.Close()
+		}
+		pipe.CloseWithError(err)
+	}()
+	defer reader.Close()
+
+	document := tgbotapi.NewDocument(author.ID, tgbotapi.FileReader{
+		Name:   "0xterminal-dataset" + export.Format.Extension(),
+		Reader: reader,
+	})
 	document.ReplyMarkup = nil
 
 	h.deleteMessage(author.ID, messageID)
@@ -105,21 +286,20 @@ func (h *Handler) CallbackDataset(u tgbotapi.Update) {
 	_, err = h.client.Send(document)
 	if err != nil {
 		log.Error("could not send dataset", sl.Err(err))
-		h.editMessage(author.ID, messageID, "<b>Failed to compose dataset</b>", nil)
+		h.sendTextMessage(author.ID, "<b>Failed to compose dataset</b>", GetMarkupBackToAdmin())
+		return
 	}
 
-	log.Info("0xterminal dataset sent")
+	log.Info("0xterminal dataset sent", slog.String("format", string(export.Format)))
 
 	content := "<b>Admin Panel</b>\n\n"
 	content += fmt.Sprintf("Logged in as <b>@%s</b>\n", author.UserName)
 	content += fmt.Sprintf("<b>ID:</b> <code>%d</code>", author.ID)
 
 	h.sendTextMessage(author.ID, content, GetMarkupAdmin())
-
-	os.Remove(path)
 }
 
-func (h *Handler) CallbackAdminPanel(u tgbotapi.Update) {
+func (h *Handler) CallbackAdminPanel(ctx context.Context, u tgbotapi.Update) {
 	author := u.CallbackQuery.From
 	messageID := u.CallbackQuery.Message.MessageID
 	log := h.log.With(
@@ -128,7 +308,7 @@ func (h *Handler) CallbackAdminPanel(u tgbotapi.Update) {
 		slog.String("id", strconv.FormatInt(author.ID, 10)),
 	)
 
-	user, err := h.storage.GetUserByTelegramID(author.ID)
+	user, err := h.storage.GetUserByTelegramID(ctx, author.ID)
 	if err != nil {
 		log.Error("could not get user from database", sl.Err(err))
 		h.editMessage(author.ID, messageID, "<b>Something went wrong... Try again later</b>", nil)
@@ -147,7 +327,7 @@ func (h *Handler) CallbackAdminPanel(u tgbotapi.Update) {
 	h.editMessage(author.ID, messageID, content, GetMarkupAdmin())
 }
 
-func (h *Handler) CallbackStats(u tgbotapi.Update) {
+func (h *Handler) CallbackStats(ctx context.Context, u tgbotapi.Update) {
 	author := u.CallbackQuery.From
 	messageID := u.CallbackQuery.Message.MessageID
 	log := h.log.With(
@@ -156,7 +336,7 @@ func (h *Handler) CallbackStats(u tgbotapi.Update) {
 		slog.String("id", strconv.FormatInt(author.ID, 10)),
 	)
 
-	user, err := h.storage.GetUserByTelegramID(author.ID)
+	user, err := h.storage.GetUserByTelegramID(ctx, author.ID)
 	if err != nil {
 		log.Error("could not get user from database", sl.Err(err))
 		h.editMessage(author.ID, messageID, "<b>Something went wrong... Try again later</b>", GetMarkupBackToAdmin())
@@ -168,7 +348,7 @@ func (h *Handler) CallbackStats(u tgbotapi.Update) {
 		return
 	}
 
-	games, err := h.storage.GetAllGames()
+	games, err := h.storage.GetAllGames(ctx)
 	if err != nil {
 		log.Error("could not get games from database", sl.Err(err))
 		h.editMessage(author.ID, messageID, "<b>Could not create statistics report</b>", GetMarkupBackToAdmin())
@@ -178,7 +358,7 @@ func (h *Handler) CallbackStats(u tgbotapi.Update) {
 	var builder strings.Builder
 	builder.WriteString(fmt.Sprintf("<b>All Time Statistics</b>\n\n<b>Total games:</b> %d\n", len(games)))
 
-	gamesStats, err := h.storage.GetGamesToUserStatistics()
+	gamesStats, err := h.storage.GetGamesToUserStatistics(ctx)
 	if err != nil {
 		log.Error("could not get games statistics from database", sl.Err(err))
 		h.editMessage(author.ID, messageID, "<b>Could not create statistics report</b>", GetMarkupBackToAdmin())
@@ -223,7 +403,7 @@ func (h *Handler) CallbackStats(u tgbotapi.Update) {
 		}
 	}
 
-	usersAmount, err := h.storage.GetUsersCount()
+	usersAmount, err := h.storage.GetUsersCount(ctx)
 	if err != nil {
 		log.Error("could not get users amount from database", sl.Err(err))
 		h.editMessage(author.ID, messageID, "<b>Could not create statistics report</b>", GetMarkupBackToAdmin())
@@ -235,7 +415,7 @@ func (h *Handler) CallbackStats(u tgbotapi.Update) {
 	h.editMessage(author.ID, messageID, builder.String(), GetMarkupBackToAdmin())
 }
 
-func (h *Handler) CallbackDailyReport(u tgbotapi.Update) {
+func (h *Handler) CallbackDailyReport(ctx context.Context, u tgbotapi.Update) {
 	author := u.CallbackQuery.From
 	messageID := u.CallbackQuery.Message.MessageID
 	log := h.log.With(
@@ -244,7 +424,7 @@ func (h *Handler) CallbackDailyReport(u tgbotapi.Update) {
 		slog.String("id", strconv.FormatInt(author.ID, 10)),
 	)
 
-	user, err := h.storage.GetUserByTelegramID(author.ID)
+	user, err := h.storage.GetUserByTelegramID(ctx, author.ID)
 	if err != nil {
 		log.Error("could not get user from database", sl.Err(err))
 		h.editMessage(author.ID, messageID, "<b>Something went wrong... Try again later</b>", GetMarkupBackToAdmin())
@@ -271,7 +451,7 @@ func (h *Handler) CallbackDailyReport(u tgbotapi.Update) {
 		date, _ = time.Parse("02-01-2006", parts[1])
 	}
 
-	report, err := h.storage.GetDailyReport(date)
+	report, err := h.storage.GetDailyReport(ctx, date)
 	if err != nil {
 		log.Error("could not get daily report from database", sl.Err(err))
 		h.editMessage(author.ID, messageID, "<b>Failed to get daily report</b>", GetMarkupBackToAdmin())
@@ -307,7 +487,7 @@ func (h *Handler) CallbackDailyReport(u tgbotapi.Update) {
 	}
 }
 
-func (h *Handler) CallbackChooseWord(u tgbotapi.Update) {
+func (h *Handler) CallbackChooseWord(ctx context.Context, u tgbotapi.Update) {
 	author := u.CallbackQuery.From
 	messageID := u.CallbackQuery.Message.MessageID
 
@@ -316,7 +496,7 @@ func (h *Handler) CallbackChooseWord(u tgbotapi.Update) {
 	h.editMessage(author.ID, messageID, fmt.Sprintf("<b>How many guessed letters in word</b> <code>%s</code>?", word), GetMarkupGuessedLetters(word))
 }
 
-func (h *Handler) CallbackChooseGuessedLetters(u tgbotapi.Update) {
+func (h *Handler) CallbackChooseGuessedLetters(ctx context.Context, u tgbotapi.Update) {
 	author := u.CallbackQuery.From
 	log := h.log.With(
 		slog.String("op", "handler.CallbackChooseGuessedLetters"),
@@ -327,9 +507,9 @@ func (h *Handler) CallbackChooseGuessedLetters(u tgbotapi.Update) {
 
 	messageID := u.CallbackQuery.Message.MessageID
 
-	_, err := h.storage.GetUserByTelegramID(author.ID)
+	_, err := h.storage.GetUserByTelegramID(ctx, author.ID)
 	if errors.Is(err, storage.ErrUserNotFound) {
-		_, err = h.storage.SaveUser(author.ID, author.UserName, author.FirstName, author.LastName)
+		_, err = h.storage.SaveUser(ctx, author.ID, author.UserName, author.FirstName, author.LastName)
 		if err != nil {
 			log.Error("could not save user to database", sl.Err(err))
 		}
@@ -343,7 +523,7 @@ func (h *Handler) CallbackChooseGuessedLetters(u tgbotapi.Update) {
 	word := parts[0]
 	guessedLetters, _ := strconv.Atoi(parts[1])
 
-	game, exists := h.games[author.ID]
+	game, exists := h.activeGame(author.ID)
 	if !exists {
 		h.editMessage(author.ID, messageID, "Use /newgame or button to start new game", GetMarkupNewGame())
 		return
@@ -351,21 +531,340 @@ func (h *Handler) CallbackChooseGuessedLetters(u tgbotapi.Update) {
 
 	game.SubmitAttempt(word, guessedLetters)
 
-	if len(game.AvailableWords()) == 1 {
-		delete(h.games, author.ID)
-		h.editMessage(author.ID, messageID, fmt.Sprintf("<b>Target word:</b> <code>%s</code>", game.Target()), GetMarkupNewGame())
+	h.showGameState(ctx, author, messageID, game)
+}
+
+func (h *Handler) CallbackAmendAttempts(ctx context.Context, u tgbotapi.Update) {
+	author := u.CallbackQuery.From
+	messageID := u.CallbackQuery.Message.MessageID
+	log := h.log.With(
+		slog.String("op", "handler.CallbackAmendAttempts"),
+		slog.String("username", author.UserName),
+		slog.String("id", strconv.FormatInt(author.ID, 10)),
+		slog.String("query", u.CallbackData()),
+	)
+
+	game, exists := h.activeGame(author.ID)
+	if !exists {
+		h.editMessage(author.ID, messageID, "Use /newgame or button to start new game", GetMarkupNewGame())
+		return
+	}
+
+	corrections := make([]terminal.Correction, 0)
+	for _, part := range strings.Split(strings.TrimPrefix(u.CallbackData(), "amend:"), ",") {
+		values := strings.Split(part, "=")
+		if len(values) != 2 {
+			log.Error("could not parse correction from callback query")
+			return
+		}
+		attempt, err := strconv.Atoi(values[0])
+		if err != nil {
+			log.Error("could not parse attempt number", sl.Err(err))
+			return
+		}
+		guessedLetters, err := strconv.Atoi(values[1])
+		if err != nil {
+			log.Error("could not parse guessed letters", sl.Err(err))
+			return
+		}
+		corrections = append(corrections, terminal.Correction{Attempt: attempt, GuessedLetters: guessedLetters})
+	}
+
+	err := game.Amend(corrections...)
+	if err != nil {
+		log.Error("could not amend attempts", sl.Err(err))
+		h.editMessage(author.ID, messageID, "<b>Something went wrong... Try again later</b>", GetMarkupNewGame())
+		return
+	}
+
+	h.showGameState(ctx, author, messageID, game)
+}
+
+// saveGame stores finished game with all its attempts, and returns ID of the stored game, or empty string, if it was not stored.
+func (h *Handler) saveGame(ctx context.Context, telegramID int64, game *terminal.Game) string {
+	log := h.log.With(
+		slog.String("op", "handler.saveGame"),
+		slog.String("id", strconv.FormatInt(telegramID, 10)),
+	)
+
+	history := game.History()
+	attempts := make([]storage.Attempt, len(history))
+	for i, attempt := range history {
+		attempts[i] = storage.Attempt{
+			Number:         i + 1,
+			Word:           attempt.Word,
+			GuessedLetters: attempt.GuessedLetters,
+		}
+	}
+
+	saved, err := h.storage.SaveGame(ctx, telegramID, game.Words(), game.Target(), game.Attempts(), attempts)
+	if err != nil {
+		log.Error("could not save game", sl.Err(err))
+		return ""
+	}
+
+	return saved.ID
+}
+
+// revokeFinishedGame deletes the stored game, if the user undid the last attempt of the finished game,
+// since its target was probably wrong. The game will be stored again, when it's finished.
+func (h *Handler) revokeFinishedGame(ctx context.Context, telegramID int64) {
+	log := h.log.With(
+		slog.String("op", "handler.revokeFinishedGame"),
+		slog.String("id", strconv.FormatInt(telegramID, 10)),
+	)
+
+	gameID, exists := h.finished.Get(telegramID)
+	if !exists {
+		return
+	}
+	h.finished.Delete(telegramID)
+
+	if err := h.storage.DeleteGame(ctx, gameID); err != nil {
+		log.Error("could not delete undone game", slog.String("game_id", gameID), sl.Err(err))
+	}
+}
+
+// showGameState edits message to show the game state after attempts was changed, and finishes the game if the target is found.
+// If messageID is zero, the state is sent in a new message.
+func (h *Handler) showGameState(ctx context.Context, author *tgbotapi.User, messageID int, game *terminal.Game) {
+	if isFinished(game) {
+		// the game is kept until the next one is started, so a mis-tap in the last attempt could be undone
+		h.finished.Delete(author.ID)
+		h.respond(author.ID, messageID, fmt.Sprintf("<b>Target word:</b> <code>%s</code>", game.Target()), GetMarkupFinishedGame())
 
 		// we'll assume that game is kinda spam, if initial words is less than 6
 		if len(game.Words()) >= 6 {
-			h.storage.SaveGame(author.ID, game.Words(), game.Target(), game.Attempts())
+			if gameID := h.saveGame(ctx, author.ID, game); gameID != "" {
+				h.finished.Set(author.ID, gameID)
+			}
 		}
 		return
 	}
 	if len(game.AvailableWords()) == 0 {
-		delete(h.games, author.ID)
-		h.editMessage(author.ID, messageID, "<b>No matching words left.</b>\n\nTry again, may be you made a mistake?", nil)
+		content := "<b>No matching words left.</b>\n\nProbably, you made a mistake in one of the attempts"
+		fixes := game.Diagnose()
+		if len(fixes) != 0 {
+			content += ". Pick the correction, that makes your attempts consistent again"
+		}
+		h.respond(author.ID, messageID, content, GetMarkupCorrections(fixes))
+		return
+	}
+
+	h.respond(author.ID, messageID, getContentPickWord(game), GetMarkupWords(game))
+}
+
+func (h *Handler) CallbackChooseStrategy(ctx context.Context, u tgbotapi.Update) {
+	author := u.CallbackQuery.From
+	messageID := u.CallbackQuery.Message.MessageID
+	log := h.log.With(
+		slog.String("op", "handler.CallbackChooseStrategy"),
+		slog.String("username", author.UserName),
+		slog.String("id", strconv.FormatInt(author.ID, 10)),
+	)
+
+	parts := strings.Split(u.CallbackData(), ":")
+	if len(parts) < 2 {
+		log.Error("could not get strategy from callback query", slog.String("query", u.CallbackData()))
+		return
+	}
+
+	strategy, err := terminal.StrategyByName(parts[1])
+	if err != nil {
+		log.Error("could not find strategy", sl.Err(err))
+		return
+	}
+
+	h.strategies.Set(author.ID, strategy)
+	minimax, _ := h.minimax.Get(author.ID)
+	probes, _ := h.probes.Get(author.ID)
+	h.editMessage(author.ID, messageID, fmt.Sprintf("<b>Strategy changed to</b> <code>%s</code>\n\nIt will be used in your next games", strategy.Name()), GetMarkupStrategies(strategy, minimax, probes))
+}
+
+func (h *Handler) CallbackChooseMinimax(ctx context.Context, u tgbotapi.Update) {
+	author := u.CallbackQuery.From
+	messageID := u.CallbackQuery.Message.MessageID
+
+	minimax := strings.TrimPrefix(u.CallbackData(), "choose-minimax:") == "on"
+	h.minimax.Set(author.ID, minimax)
+
+	strategy, exists := h.strategies.Get(author.ID)
+	if !exists {
+		strategy = DefaultStrategy
+	}
+
+	probes, _ := h.probes.Get(author.ID)
+
+	content := "<b>Minimax mode disabled</b>\n\nWords will be sorted by the strategy in your next games"
+	if minimax {
+		content = "<b>Minimax mode enabled</b>\n\nThe word, that guarantees the fewest attempts, will be put first in your next games, if it's found in time"
+	}
+	h.editMessage(author.ID, messageID, content, GetMarkupStrategies(strategy, minimax, probes))
+}
+
+func (h *Handler) CallbackChooseProbes(ctx context.Context, u tgbotapi.Update) {
+	author := u.CallbackQuery.From
+	messageID := u.CallbackQuery.Message.MessageID
+
+	probes := strings.TrimPrefix(u.CallbackData(), "choose-probes:") == "on"
+	h.probes.Set(author.ID, probes)
+
+	strategy, exists := h.strategies.Get(author.ID)
+	if !exists {
+		strategy = DefaultStrategy
+	}
+	minimax, _ := h.minimax.Get(author.ID)
+
+	content := "<b>Probes mode disabled</b>\n\nOnly words, that could be the target, will be suggested in your next games"
+	if probes {
+		content = "<b>Probes mode enabled</b>\n\nAlready eliminated word will be suggested in your next games, if it splits the rest words better"
+	}
+	h.editMessage(author.ID, messageID, content, GetMarkupStrategies(strategy, minimax, probes))
+}
+
+func (h *Handler) CallbackUndo(ctx context.Context, u tgbotapi.Update) {
+	author := u.CallbackQuery.From
+	messageID := u.CallbackQuery.Message.MessageID
+
+	game, exists := h.games.Get(author.ID)
+	if !exists {
+		h.editMessage(author.ID, messageID, "<b>You have no started games</b>\n\nUse /newgame or button to start new one", GetMarkupNewGame())
+		return
+	}
+
+	attempt, err := game.Undo()
+	if err != nil {
+		response := tgbotapi.NewCallback(u.CallbackQuery.ID, "No attempts to undo")
+		h.client.Request(response)
+		return
+	}
+	h.revokeFinishedGame(ctx, author.ID)
+
+	h.editMessage(author.ID, messageID, getContentUndoneAttempt(attempt)+"\n\n"+getContentPickWord(game), GetMarkupWords(game))
+}
+
+func (h *Handler) CallbackWhy(ctx context.Context, u tgbotapi.Update) {
+	author := u.CallbackQuery.From
+	messageID := u.CallbackQuery.Message.MessageID
+
+	game, exists := h.games.Get(author.ID)
+	if !exists {
+		h.editMessage(author.ID, messageID, "<b>Use /newgame or button to start new game</b>", GetMarkupNewGame())
+		return
+	}
+
+	word := strings.TrimPrefix(u.CallbackData(), "why:")
+	h.editMessage(author.ID, messageID, getContentBreakdown(game, word), GetMarkupBreakdown(word))
+}
+
+func (h *Handler) CallbackAudit(ctx context.Context, u tgbotapi.Update) {
+	author := u.CallbackQuery.From
+	messageID := u.CallbackQuery.Message.MessageID
+	log := h.log.With(
+		slog.String("op", "handler.CallbackAudit"),
+		slog.String("username", author.UserName),
+		slog.String("id", strconv.FormatInt(author.ID, 10)),
+		slog.String("query", u.CallbackData()),
+	)
+
+	user, err := h.storage.GetUserByTelegramID(ctx, author.ID)
+	if err != nil {
+		log.Error("could not get user from database", sl.Err(err))
+		h.editMessage(author.ID, messageID, "<b>Something went wrong... Try again later</b>", GetMarkupBackToAdmin())
+		return
+	}
+
+	if !user.IsAdmin {
+		h.editMessage(author.ID, messageID, "<b>You are not permitted to use this action</b>", GetMarkupBackToAdmin())
+		return
+	}
+
+	games, err := h.storage.GetAllGames(ctx)
+	if err != nil {
+		log.Error("could not get games from database", sl.Err(err))
+		h.editMessage(author.ID, messageID, "<b>Could not audit stored games</b>", GetMarkupBackToAdmin())
+		return
+	}
+
+	report := storage.Audit(games)
+	ids := report.GameIDs()
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("<b>Games Audit</b>\n\n<b>Total games:</b> %d\n<b>Games with problems:</b> %d\n\n", report.TotalGames, len(ids)))
+	builder.WriteString(fmt.Sprintf(" - <b>%d</b> targets not in the word list\n", report.Count(storage.ProblemTargetNotInWords)))
+	builder.WriteString(fmt.Sprintf(" - <b>%d</b> word lists of mixed lengths\n", report.Count(storage.ProblemMixedLengths)))
+	builder.WriteString(fmt.Sprintf(" - <b>%d</b> conflicting targets for the same word list\n", report.Count(storage.ProblemConflictingTarget)))
+
+	switch strings.TrimPrefix(u.CallbackData(), "audit") {
+	case ":flag":
+		if err = h.storage.FlagGames(ctx, ids); err != nil {
+			log.Error("could not flag games", sl.Err(err))
+			h.editMessage(author.ID, messageID, "<b>Could not flag games</b>", GetMarkupBackToAdmin())
+			return
+		}
+		log.Info("games flagged", slog.Int("amount", len(ids)))
+		builder.WriteString(fmt.Sprintf("\n<b>Flagged %d games</b>", len(ids)))
+	case ":quarantine":
+		if err = h.storage.QuarantineGames(ctx, ids); err != nil {
+			log.Error("could not quarantine games", sl.Err(err))
+			h.editMessage(author.ID, messageID, "<b>Could not quarantine games</b>", GetMarkupBackToAdmin())
+			return
+		}
+		log.Info("games quarantined", slog.Int("amount", len(ids)))
+		builder.WriteString(fmt.Sprintf("\n<b>Quarantined %d games</b>", len(ids)))
+		ids = nil
+	}
+
+	h.editMessage(author.ID, messageID, builder.String(), GetMarkupAudit(len(ids) != 0))
+}
+
+func (h *Handler) CallbackPracticeGuess(ctx context.Context, u tgbotapi.Update) {
+	author := u.CallbackQuery.From
+	messageID := u.CallbackQuery.Message.MessageID
+	log := h.log.With(
+		slog.String("op", "handler.CallbackPracticeGuess"),
+		slog.String("username", author.UserName),
+		slog.String("id", strconv.FormatInt(author.ID, 10)),
+		slog.String("query", u.CallbackData()),
+	)
+
+	practice, exists := h.practices.Get(author.ID)
+	if !exists {
+		h.editMessage(author.ID, messageID, "<b>You have no started practice</b>\n\nUse /practice to start new one", nil)
+		return
+	}
+
+	_, err := practice.Guess(strings.TrimPrefix(u.CallbackData(), "practice-guess:"))
+	if err != nil {
+		log.Error("could not submit practice guess", sl.Err(err))
+
+		text := "Something went wrong... Try again later"
+		switch {
+		case errors.Is(err, terminal.ErrPracticeFinished):
+			text = "Practice is already finished"
+		case errors.Is(err, terminal.ErrUnknownWord):
+			text = "Word is not in the list"
+		}
+		response := tgbotapi.NewCallback(u.CallbackQuery.ID, text)
+		h.client.Request(response)
+		return
+	}
+
+	if !practice.Finished() {
+		h.editMessage(author.ID, messageID, getContentPractice(practice, nil), GetMarkupPractice(practice))
 		return
 	}
 
-	h.editMessage(author.ID, messageID, fmt.Sprintf("<b>Pick one of %d words in the list</b>", len(game.AvailableWords())), GetMarkupWords(game.AvailableWords()))
+	h.practices.Delete(author.ID)
+
+	strategy, exists := h.strategies.Get(author.ID)
+	if !exists {
+		strategy = DefaultStrategy
+	}
+	solver, err := practice.SolverPlay(terminal.WithStrategy(strategy))
+	if err != nil {
+		log.Error("could not simulate solver play", sl.Err(err))
+	}
+
+	h.editMessage(author.ID, messageID, getContentPractice(practice, solver), nil)
 }
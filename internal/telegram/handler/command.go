@@ -1,17 +1,20 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"strconv"
 	"terminal/internal/storage"
+	"terminal/internal/terminal"
 	"terminal/pkg/log/sl"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-func (h *Handler) CommandStart(u tgbotapi.Update) {
+func (h *Handler) CommandStart(ctx context.Context, u tgbotapi.Update) {
 	author := u.Message.From
 	log := h.log.With(
 		slog.String("op", "handler.CommandStart"),
@@ -21,7 +24,7 @@ func (h *Handler) CommandStart(u tgbotapi.Update) {
 
 	h.sendSticker(author.ID, GreetingSticker)
 
-	_, err := h.storage.SaveUser(author.ID, author.UserName, author.FirstName, author.LastName)
+	_, err := h.storage.SaveUser(ctx, author.ID, author.UserName, author.FirstName, author.LastName)
 	if err != nil && !errors.Is(err, storage.ErrUserAlreadyExists) {
 		log.Error("could not save user to database", sl.Err(err))
 	}
@@ -33,13 +36,13 @@ func (h *Handler) CommandStart(u tgbotapi.Update) {
 		"Although there are still games where you may not be able to guess a given word even after 4 attempts, but they're pretty rare"
 	h.sendTextMessage(author.ID, content, GetMarkupNewGame())
 
-	h.stages[author.ID] = None
+	h.stages.Set(author.ID, None)
 }
 
-func (h *Handler) CommandGame(u tgbotapi.Update) {
+func (h *Handler) CommandGame(ctx context.Context, u tgbotapi.Update) {
 	author := u.Message.From
 
-	game, exists := h.games[author.ID]
+	game, exists := h.activeGame(author.ID)
 	if exists {
 		content := "<b>You already have started game. Do you want to continue it?</b>\n\n<b>Words:</b>\n<code>"
 		for _, word := range game.AvailableWords() {
@@ -49,11 +52,11 @@ func (h *Handler) CommandGame(u tgbotapi.Update) {
 		h.sendTextMessage(author.ID, content, GetMarkupGameMenu())
 	} else {
 		h.sendTextMessage(author.ID, "Send me list of words in your $TERMINAL game", nil)
-		h.stages[author.ID] = WaitingWordList
+		h.stages.Set(author.ID, WaitingWordList)
 	}
 }
 
-func (h *Handler) CommandAdmin(u tgbotapi.Update) {
+func (h *Handler) CommandAdmin(ctx context.Context, u tgbotapi.Update) {
 	author := u.Message.From
 	log := h.log.With(
 		slog.String("op", "handler.CommandDataset"),
@@ -61,7 +64,7 @@ func (h *Handler) CommandAdmin(u tgbotapi.Update) {
 		slog.String("id", strconv.FormatInt(author.ID, 10)),
 	)
 
-	user, err := h.storage.GetUserByTelegramID(author.ID)
+	user, err := h.storage.GetUserByTelegramID(ctx, author.ID)
 	if err != nil {
 		log.Error("could not get user from database", sl.Err(err))
 		return
@@ -77,3 +80,80 @@ func (h *Handler) CommandAdmin(u tgbotapi.Update) {
 
 	h.sendTextMessage(author.ID, content, GetMarkupAdmin())
 }
+
+func (h *Handler) CommandStrategy(ctx context.Context, u tgbotapi.Update) {
+	author := u.Message.From
+
+	strategy, exists := h.strategies.Get(author.ID)
+	if !exists {
+		strategy = DefaultStrategy
+	}
+
+	minimax, _ := h.minimax.Get(author.ID)
+	probes, _ := h.probes.Get(author.ID)
+
+	content := "<b>Choose the strategy to sort words in your next games</b>\n\n" +
+		"In <b>minimax</b> mode the word, that guarantees the fewest attempts, is put first, if it's found in time\n\n" +
+		"In <b>probes</b> mode already eliminated word is suggested, if it splits the rest words better"
+	h.sendTextMessage(author.ID, content, GetMarkupStrategies(strategy, minimax, probes))
+}
+
+func (h *Handler) CommandUndo(ctx context.Context, u tgbotapi.Update) {
+	author := u.Message.From
+
+	game, exists := h.games.Get(author.ID)
+	if !exists {
+		h.sendTextMessage(author.ID, "<b>You have no started games</b>\n\nUse /newgame or button to start new one", GetMarkupNewGame())
+		return
+	}
+
+	attempt, err := game.Undo()
+	if err != nil {
+		h.sendTextMessage(author.ID, "<b>There are no attempts to undo</b>", nil)
+		return
+	}
+	h.revokeFinishedGame(ctx, author.ID)
+
+	h.sendTextMessage(author.ID, getContentUndoneAttempt(attempt)+"\n\n"+getContentPickWord(game), GetMarkupWords(game))
+}
+
+func (h *Handler) CommandPractice(ctx context.Context, u tgbotapi.Update) {
+	author := u.Message.From
+	log := h.log.With(
+		slog.String("op", "handler.CommandPractice"),
+		slog.String("username", author.UserName),
+		slog.String("id", strconv.FormatInt(author.ID, 10)),
+	)
+
+	var words []string
+	var target string
+
+	game, err := h.storage.GetRandomGame(ctx)
+	switch {
+	case errors.Is(err, storage.ErrGameNotFound):
+		// there are no recorded games yet, so practice on a synthetic one
+		list, err := terminal.NewGenerator(terminal.DefaultListStats, time.Now().UnixNano()).Generate()
+		if err != nil {
+			log.Error("could not generate word list", sl.Err(err))
+			h.sendTextMessage(author.ID, "<b>Something went wrong... Try again later</b>", nil)
+			return
+		}
+		words, target = list.Words, list.Target
+	case err != nil:
+		log.Error("could not get random game from database", sl.Err(err))
+		h.sendTextMessage(author.ID, "<b>Something went wrong... Try again later</b>", nil)
+		return
+	default:
+		words, target = game.Words, game.Target
+	}
+
+	practice, err := terminal.NewPractice(words, target, terminal.DefaultAttemptBudget)
+	if err != nil {
+		log.Error("could not start practice", sl.Err(err))
+		h.sendTextMessage(author.ID, "<b>Something went wrong... Try again later</b>", nil)
+		return
+	}
+
+	h.practices.Set(author.ID, practice)
+	h.sendTextMessage(author.ID, getContentPractice(practice, nil), GetMarkupPractice(practice))
+}
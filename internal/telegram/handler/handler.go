@@ -1,11 +1,18 @@
 package handler
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
 	"terminal/internal/ocr"
 	"terminal/internal/storage"
 	"terminal/internal/terminal"
+	"terminal/internal/terminal/dataset"
 	"terminal/pkg/log/sl"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -15,31 +22,346 @@ const (
 	WaitingSticker  = tgbotapi.FileID("CAACAgIAAxkBAAIEYGZgG0yU3WUeIN7d_brzaqUEchPtAAIaSQACsCNJSmO4cga8SZwHNQQ")
 )
 
+// SolverBudget limits the game-tree search, that runs after each attempt, if the user enabled minimax mode.
+const SolverBudget = 300 * time.Millisecond
+
+// SimilarityThreshold is a minimal share of common words, a known game must have with the word list, to suggest its target.
+// It allows one misread word in lists of 6 and more words.
+const SimilarityThreshold = 0.7
+
+// MaxCandidates limits amount of suggested targets of known games.
+const MaxCandidates = 3
+
+// DefaultStrategy is used for users, that haven't picked a strategy with /strategy.
+var DefaultStrategy = terminal.StrategySexyIndex
+
+// PriorsRefreshInterval is how often target priors are rebuilt from recorded games.
+const PriorsRefreshInterval = 10 * time.Minute
+
 type Stage uint8
 
 const (
 	None = iota
 	WaitingWordList
+	WaitingDatasetUser
 )
 
 type Handler struct {
-	log     *slog.Logger
-	client  *tgbotapi.BotAPI
-	storage storage.Storage
-	ocr     *ocr.Client
-	games   map[int64]*terminal.Game
-	stages  map[int64]Stage
+	log        *slog.Logger
+	client     *tgbotapi.BotAPI
+	storage    storage.Storage
+	ocr        *ocr.Client
+	games      *userMap[*terminal.Game]
+	finished   *userMap[string] // value: ID of the stored finished game, while its last attempt could be undone
+	stages     *userMap[Stage]
+	strategies *userMap[terminal.Strategy]
+	minimax    *userMap[bool]
+	probes     *userMap[bool]
+	practices  *userMap[*terminal.Practice]
+	// datasetExports keeps export settings, picked by admin, while the user filter is awaited
+	datasetExports *userMap[DatasetExport]
+	locks          *userMap[*sync.Mutex]
+	anonymization  dataset.Anonymization
+
+	priorsMu        sync.Mutex
+	priors          terminal.Priors
+	priorsUpdatedAt time.Time
 }
 
-func New(logger *slog.Logger, client *tgbotapi.BotAPI, st storage.Storage, o *ocr.Client) *Handler {
+func New(logger *slog.Logger, client *tgbotapi.BotAPI, st storage.Storage, o *ocr.Client, anonymization dataset.Anonymization) *Handler {
 	return &Handler{
-		log:     logger,
-		client:  client,
-		storage: st,
-		ocr:     o,
-		games:   make(map[int64]*terminal.Game, 0),
-		stages:  make(map[int64]Stage, 0),
+		log:            logger,
+		client:         client,
+		storage:        st,
+		ocr:            o,
+		games:          newUserMap[*terminal.Game](),
+		finished:       newUserMap[string](),
+		stages:         newUserMap[Stage](),
+		strategies:     newUserMap[terminal.Strategy](),
+		minimax:        newUserMap[bool](),
+		probes:         newUserMap[bool](),
+		practices:      newUserMap[*terminal.Practice](),
+		datasetExports: newUserMap[DatasetExport](),
+		locks:          newUserMap[*sync.Mutex](),
+		anonymization:  anonymization,
+	}
+}
+
+// Lock makes updates of the user to be handled one at a time, since they change the same game and state.
+// Returned function releases the lock.
+func (h *Handler) Lock(telegramID int64) func() {
+	lock := h.locks.GetOrSet(telegramID, &sync.Mutex{})
+	lock.Lock()
+	return lock.Unlock
+}
+
+// userMap keeps a value per user. It's safe to use from updates of different users at once.
+type userMap[V any] struct {
+	mu     sync.RWMutex
+	values map[int64]V
+}
+
+func newUserMap[V any]() *userMap[V] {
+	return &userMap[V]{
+		values: make(map[int64]V),
+	}
+}
+
+func (m *userMap[V]) Get(telegramID int64) (V, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	value, exists := m.values[telegramID]
+	return value, exists
+}
+
+func (m *userMap[V]) Set(telegramID int64, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[telegramID] = value
+}
+
+// GetOrSet returns the value of the user, or sets it to provided one, if the user has no value yet.
+func (m *userMap[V]) GetOrSet(telegramID int64, value V) V {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, exists := m.values[telegramID]; exists {
+		return existing
+	}
+	m.values[telegramID] = value
+	return value
+}
+
+func (m *userMap[V]) Delete(telegramID int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.values, telegramID)
+}
+
+// activeGame returns the game of the user, unless it's finished. Finished games are kept only to undo their last attempt.
+func (h *Handler) activeGame(telegramID int64) (*terminal.Game, bool) {
+	game, exists := h.games.Get(telegramID)
+	if !exists || isFinished(game) {
+		return nil, false
+	}
+	return game, true
+}
+
+// setGame replaces the game of the user. The previous one could no longer be undone.
+func (h *Handler) setGame(telegramID int64, game *terminal.Game) {
+	h.games.Set(telegramID, game)
+	h.finished.Delete(telegramID)
+}
+
+func isFinished(game *terminal.Game) bool {
+	return len(game.AvailableWords()) == 1
+}
+
+// newGame creates a game for the user with options, that the user picked before.
+func (h *Handler) newGame(ctx context.Context, telegramID int64, words []string) (*terminal.Game, error) {
+	return terminal.New(words, h.gameOptions(ctx, telegramID)...)
+}
+
+// gameOptions returns options, that the user picked before, and target priors, built from recorded games.
+func (h *Handler) gameOptions(ctx context.Context, telegramID int64) []terminal.Option {
+	log := h.log.With(
+		slog.String("op", "handler.gameOptions"),
+		slog.String("id", strconv.FormatInt(telegramID, 10)),
+	)
+
+	strategy, exists := h.strategies.Get(telegramID)
+	if !exists {
+		strategy = DefaultStrategy
+	}
+	opts := []terminal.Option{terminal.WithStrategy(strategy)}
+	if minimax, _ := h.minimax.Get(telegramID); minimax {
+		opts = append(opts, terminal.WithMinimax(SolverBudget))
+	}
+	if probes, _ := h.probes.Get(telegramID); probes {
+		opts = append(opts, terminal.WithProbes())
+	}
+
+	priors, err := h.targetPriors(ctx)
+	if err != nil {
+		log.Error("could not get words statistics from database", sl.Err(err))
+	}
+	if priors != nil {
+		opts = append(opts, terminal.WithPriors(priors))
+	}
+
+	return opts
+}
+
+// targetPriors returns priors, built from recorded games. They are cached, since building them aggregates all games,
+// and rebuilt once in PriorsRefreshInterval. If they could not be rebuilt, the previous ones are returned with the error.
+func (h *Handler) targetPriors(ctx context.Context) (terminal.Priors, error) {
+	h.priorsMu.Lock()
+	defer h.priorsMu.Unlock()
+
+	if h.priors != nil && time.Since(h.priorsUpdatedAt) < PriorsRefreshInterval {
+		return h.priors, nil
 	}
+
+	stats, err := h.storage.GetWordStats(ctx)
+	if err != nil {
+		return h.priors, err
+	}
+
+	frequencies := make([]terminal.WordFrequency, 0, len(stats))
+	for _, stat := range stats {
+		frequencies = append(frequencies, terminal.WordFrequency{
+			Word:        stat.Word,
+			Appearances: stat.Appearances,
+			Targets:     stat.Targets,
+		})
+	}
+
+	h.priors = terminal.NewPriors(frequencies)
+	h.priorsUpdatedAt = time.Now()
+
+	return h.priors, nil
+}
+
+// getContentPickWord returns message content, that asks user to pick next word in the game.
+func getContentPickWord(game *terminal.Game) string {
+	content := fmt.Sprintf("<b>Pick one of %d words in the list</b>", len(game.AvailableWords()))
+
+	if solution, ok := game.Solution(); ok && solution.Depth > 1 {
+		content += fmt.Sprintf("\n\nTarget is guaranteed to be found in <b>%d</b> attempts, if you start with <code>%s</code>", solution.Depth, solution.Word)
+	}
+
+	if probe, ok := game.Probe(); ok {
+		content += fmt.Sprintf("\n\n💡 Already eliminated <code>%s</code> splits the rest words better, but it can't be the target", probe)
+	}
+
+	forecast := game.Forecast()
+	content += fmt.Sprintf("\n\n<b>Attempts left:</b> %d\n<b>Chance to find the target in time:</b> %.0f%%", game.AttemptsLeft(), forecast.SuccessProbability*100)
+	if !forecast.Guaranteed {
+		content += "\n\n⚠️ <b>Target can no longer be guaranteed to be found within attempts left</b>"
+	}
+
+	return content
+}
+
+// MaxBreakdownWords limits amount of words, listed for each likeness value in the partition breakdown.
+const MaxBreakdownWords = 5
+
+// getContentBreakdown returns message content, that explains how the word splits available words.
+func getContentBreakdown(game *terminal.Game, word string) string {
+	partition := game.Partition(word)
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("<b>Why</b> <code>%s</code>?\n\n", word))
+	builder.WriteString(fmt.Sprintf("There are %d words left. Depending on amount of guessed letters, these words will remain:\n\n", partition.Total()))
+
+	for n, size := range partition.Buckets {
+		if size == 0 {
+			continue
+		}
+
+		if n == len(partition.Buckets)-1 {
+			builder.WriteString(fmt.Sprintf(" - <b>All</b>: it's the target (%.0f%%)\n", partition.Mass[n]*100))
+			continue
+		}
+
+		if size == 1 {
+			builder.WriteString(fmt.Sprintf(" - <b>%d</b>: %d word (%.0f%%)", n, size, partition.Mass[n]*100))
+		} else {
+			builder.WriteString(fmt.Sprintf(" - <b>%d</b>: %d words (%.0f%%)", n, size, partition.Mass[n]*100))
+		}
+		if size <= MaxBreakdownWords {
+			builder.WriteString(" <code>" + strings.Join(game.Remaining(word, n), " ") + "</code>")
+		}
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString(fmt.Sprintf("\n<b>Strategy:</b> %s, score %.3f (lower is better)", game.Strategy().Name(), game.Strategy().Score(partition)))
+
+	return builder.String()
+}
+
+// getContentCandidates returns message content with targets of known games, which word lists are similar to the game one.
+func getContentCandidates(candidates []storage.Candidate) string {
+	var builder strings.Builder
+	builder.WriteString("<b>Found games with similar words list</b>\n\nProbably, the target is one of these:\n\n")
+
+	for i, candidate := range candidates {
+		if i == MaxCandidates {
+			break
+		}
+
+		if candidate.Games == 1 {
+			builder.WriteString(fmt.Sprintf(" - <code>%s</code>: %.0f%% confidence, %d game\n", candidate.Target, candidate.Confidence*100, candidate.Games))
+		} else {
+			builder.WriteString(fmt.Sprintf(" - <code>%s</code>: %.0f%% confidence, %d games\n", candidate.Target, candidate.Confidence*100, candidate.Games))
+		}
+	}
+
+	return builder.String()
+}
+
+// getContentPractice returns message content with practice attempts, and the solver play, when practice is finished.
+func getContentPractice(practice *terminal.Practice, solver []terminal.Attempt) string {
+	var builder strings.Builder
+	builder.WriteString("<b>Practice</b>\n\n")
+
+	for _, attempt := range practice.History() {
+		builder.WriteString(fmt.Sprintf("<code>&gt;%s</code> Likeness=%d\n", attempt.Word, attempt.GuessedLetters))
+	}
+
+	switch {
+	case practice.Won():
+		builder.WriteString(fmt.Sprintf("\n✅ <b>Access granted!</b> You found the target in %d attempts\n", len(practice.History())))
+	case practice.Finished():
+		builder.WriteString(fmt.Sprintf("\n❌ <b>Terminal locked.</b> The target was <code>%s</code>\n", practice.Target()))
+	default:
+		if len(practice.History()) != 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(fmt.Sprintf("Pick a word. <b>Attempts left:</b> %d", practice.AttemptsLeft()))
+		return builder.String()
+	}
+
+	if solver != nil {
+		builder.WriteString(fmt.Sprintf("\n<b>Solver would find it in %d attempts:</b>\n", len(solver)))
+		for _, attempt := range solver {
+			builder.WriteString(fmt.Sprintf("<code>&gt;%s</code> Likeness=%d\n", attempt.Word, attempt.GuessedLetters))
+		}
+	}
+
+	return builder.String()
+}
+
+// getContentUndoneAttempt returns message content, that describes undone attempt.
+func getContentUndoneAttempt(attempt terminal.Attempt) string {
+	return fmt.Sprintf("<b>Undone attempt:</b> <code>%s</code> with %d guessed letters", attempt.Word, attempt.GuessedLetters)
+}
+
+// getContentDatasetExport returns message content, that describes picked dataset export settings.
+func getContentDatasetExport(export DatasetExport) string {
+	length := "any"
+	if export.WordLength != 0 {
+		length = strconv.Itoa(export.WordLength)
+	}
+
+	var builder strings.Builder
+	builder.WriteString("<b>Dataset Export</b>\n\n")
+	builder.WriteString(fmt.Sprintf("<b>Format:</b> %s\n", strings.ToUpper(string(export.Format))))
+	builder.WriteString(fmt.Sprintf("<b>Period:</b> %s\n", periodLabel(export.Period)))
+	builder.WriteString(fmt.Sprintf("<b>Word length:</b> %s\n", length))
+	builder.WriteString(fmt.Sprintf("<b>Min words:</b> %s\n", minWordsLabel(export.MinWords)))
+	builder.WriteString(fmt.Sprintf("<b>User:</b> %s\n", userLabel(export.TelegramID)))
+	if export.Anonymous {
+		builder.WriteString("<b>Anonymous:</b> yes\n\n")
+	} else {
+		builder.WriteString("<b>Anonymous:</b> no\n\n")
+	}
+	builder.WriteString("Pick format and filters, then press <b>Export</b>")
+
+	return builder.String()
 }
 
 func (h *Handler) sendTextMessage(chatID int64, content string, markup *tgbotapi.InlineKeyboardMarkup) (tgbotapi.Message, error) {
@@ -77,6 +399,14 @@ func (h *Handler) editMessage(chatID int64, messageID int, content string, marku
 	return message, err
 }
 
+// respond edits the message, or sends a new one, if messageID is zero.
+func (h *Handler) respond(chatID int64, messageID int, content string, markup *tgbotapi.InlineKeyboardMarkup) (tgbotapi.Message, error) {
+	if messageID == 0 {
+		return h.sendTextMessage(chatID, content, markup)
+	}
+	return h.editMessage(chatID, messageID, content, markup)
+}
+
 func (h *Handler) sendSticker(chatID int64, sticker tgbotapi.RequestFileData) (tgbotapi.Message, error) {
 	log := h.log.With(
 		slog.String("op", "handler.sendSticker"),
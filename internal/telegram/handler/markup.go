@@ -2,7 +2,11 @@ package handler
 
 import (
 	"fmt"
+	"strings"
+	"terminal/internal/terminal"
+	"terminal/internal/terminal/dataset"
 	"time"
+	"unicode/utf8"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -18,10 +22,125 @@ func GetMarkupAdmin() *tgbotapi.InlineKeyboardMarkup {
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("Dataset", "dataset"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Audit", "audit"),
+		),
 	)
 	return &markup
 }
 
+func GetMarkupAudit(problems bool) *tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0)
+
+	if problems {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Flag", "audit:flag"),
+			tgbotapi.NewInlineKeyboardButtonData("Quarantine", "audit:quarantine"),
+		))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("« Back", "admin-panel"),
+	))
+
+	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	return &markup
+}
+
+func GetMarkupDataset(export DatasetExport) *tgbotapi.InlineKeyboardMarkup {
+	button := func(label string, selected bool, option DatasetExport) tgbotapi.InlineKeyboardButton {
+		if selected {
+			label = "• " + label
+		}
+		return tgbotapi.NewInlineKeyboardButtonData(label, "dataset:"+option.String())
+	}
+
+	formats := make([]tgbotapi.InlineKeyboardButton, 0)
+	for _, format := range dataset.Formats() {
+		option := export
+		option.Format = format
+		formats = append(formats, button(strings.ToUpper(string(format)), format == export.Format, option))
+	}
+
+	periods := make([]tgbotapi.InlineKeyboardButton, 0)
+	for _, period := range DatasetPeriods {
+		option := export
+		option.Period = period
+		periods = append(periods, button(periodLabel(period), period == export.Period, option))
+	}
+
+	lengths := make([]tgbotapi.InlineKeyboardButton, 0)
+	for _, length := range DatasetWordLengths {
+		option := export
+		option.WordLength = length
+		label := "Any"
+		if length != 0 {
+			label = fmt.Sprintf("%d", length)
+		}
+		lengths = append(lengths, button(label, length == export.WordLength, option))
+	}
+
+	minWords := make([]tgbotapi.InlineKeyboardButton, 0)
+	for _, n := range DatasetMinWords {
+		option := export
+		option.MinWords = n
+		minWords = append(minWords, button(minWordsLabel(n), n == export.MinWords, option))
+	}
+
+	// user is picked by sending telegram ID, so the button either asks for it, or resets it
+	user := tgbotapi.NewInlineKeyboardButtonData("User: any", "dataset-user:"+export.String())
+	if export.TelegramID != 0 {
+		option := export
+		option.TelegramID = 0
+		user = button(fmt.Sprintf("User: %d ✕", export.TelegramID), true, option)
+	}
+
+	anonymous := export
+	anonymous.Anonymous = !export.Anonymous
+
+	markup := tgbotapi.NewInlineKeyboardMarkup(
+		formats,
+		periods,
+		lengths,
+		minWords,
+		tgbotapi.NewInlineKeyboardRow(
+			user,
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			button("Anonymous", export.Anonymous, anonymous),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Export", "dataset-export:"+export.String()),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("« Back", "admin-panel"),
+		),
+	)
+	return &markup
+}
+
+func periodLabel(days int) string {
+	if days == 0 {
+		return "All time"
+	}
+	return fmt.Sprintf("%dd", days)
+}
+
+func minWordsLabel(n int) string {
+	if n == 0 {
+		return "Any"
+	}
+	return fmt.Sprintf("%d+", n)
+}
+
+func userLabel(telegramID int64) string {
+	if telegramID == 0 {
+		return "Any"
+	}
+	return fmt.Sprintf("%d", telegramID)
+}
+
 func GetmarkupDailyReport(date time.Time) *tgbotapi.InlineKeyboardMarkup {
 	markup := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
@@ -60,12 +179,25 @@ func GetMarkupGameMenu() *tgbotapi.InlineKeyboardMarkup {
 	return &markup
 }
 
-func GetMarkupWords(words []string) *tgbotapi.InlineKeyboardMarkup {
+func GetMarkupWords(game *terminal.Game) *tgbotapi.InlineKeyboardMarkup {
 	rows := make([][]tgbotapi.InlineKeyboardButton, 0)
 
-	for _, word := range words {
+	if probe, ok := game.Probe(); ok {
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(word, fmt.Sprintf("choose-word:%s", word)),
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%s · probe", probe), fmt.Sprintf("choose-word:%s", probe)),
+		))
+	}
+
+	for _, word := range game.AvailableWords() {
+		label := fmt.Sprintf("%s · %.0f%%", word, game.Probability(word)*100)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("choose-word:%s", word)),
+		))
+	}
+
+	if len(game.History()) > 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("↶ Undo", "undo"),
 		))
 	}
 
@@ -74,8 +206,42 @@ func GetMarkupWords(words []string) *tgbotapi.InlineKeyboardMarkup {
 	return &markup
 }
 
+// MaxCorrections limits amount of corrections, suggested to user, when no words left in the game.
+const MaxCorrections = 8
+
+func GetMarkupCorrections(fixes [][]terminal.Correction) *tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0)
+
+	for i, fix := range fixes {
+		if i == MaxCorrections {
+			break
+		}
+
+		labels := make([]string, 0, len(fix))
+		values := make([]string, 0, len(fix))
+		for _, correction := range fix {
+			labels = append(labels, fmt.Sprintf("%s have %d", strings.ToUpper(correction.Word), correction.GuessedLetters))
+			values = append(values, fmt.Sprintf("%d=%d", correction.Attempt, correction.GuessedLetters))
+		}
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("Did %s matches?", strings.Join(labels, " and ")), "amend:"+strings.Join(values, ",")),
+		))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("↶ Undo", "undo"),
+		tgbotapi.NewInlineKeyboardButtonData("Start new game", "start-new-game"),
+	))
+
+	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	return &markup
+}
+
 func GetMarkupGuessedLetters(word string) *tgbotapi.InlineKeyboardMarkup {
-	if len(word) > 21 {
+	length := utf8.RuneCountInString(word)
+	if length > 21 {
 		return getHugeMarkupGuessedLetters(word)
 	}
 	// rules describes how many buttons should be in each row depending on the word length
@@ -105,11 +271,11 @@ func GetMarkupGuessedLetters(word string) *tgbotapi.InlineKeyboardMarkup {
 	rows := make([][]tgbotapi.InlineKeyboardButton, 0)
 	rows = append(rows, []tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData("None", fmt.Sprintf("choose-guessed-letters:%s:%d", word, 0))})
 
-	rule := rules[len(word)-1]
+	rule := rules[length-1]
 	i := 1
 	for ri := range rule {
 		row := make([]tgbotapi.InlineKeyboardButton, 0)
-		for rule[ri] != 0 && i < len(word) {
+		for rule[ri] != 0 && i < length {
 			row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d", i), fmt.Sprintf("choose-guessed-letters:%s:%d", word, i)))
 			i++
 			rule[ri]--
@@ -117,10 +283,11 @@ func GetMarkupGuessedLetters(word string) *tgbotapi.InlineKeyboardMarkup {
 		rows = append(rows, row)
 	}
 
-	rows = append(rows, []tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData("All", fmt.Sprintf("choose-guessed-letters:%s:%d", word, len(word)))})
+	rows = append(rows, []tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData("All", fmt.Sprintf("choose-guessed-letters:%s:%d", word, length))})
 
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 		tgbotapi.NewInlineKeyboardButtonData("« Back", "words-list"),
+		tgbotapi.NewInlineKeyboardButtonData("Why?", fmt.Sprintf("why:%s", word)),
 	))
 
 	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
@@ -129,24 +296,70 @@ func GetMarkupGuessedLetters(word string) *tgbotapi.InlineKeyboardMarkup {
 }
 
 func getHugeMarkupGuessedLetters(word string) *tgbotapi.InlineKeyboardMarkup {
+	length := utf8.RuneCountInString(word)
+
 	rows := make([][]tgbotapi.InlineKeyboardButton, 0)
 	rows = append(rows, []tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData("None", fmt.Sprintf("choose-guessed-letters:%s:%d", word, 0))})
 
-	for i := 1; i < len(word); i += 5 {
+	for i := 1; i < length; i += 5 {
 		row := make([]tgbotapi.InlineKeyboardButton, 0)
-		for j := 0; len(row) < 5 && i+j < len(word); j++ {
+		for j := 0; len(row) < 5 && i+j < length; j++ {
 			row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d", i+j), fmt.Sprintf("choose-guessed-letters:%s:%d", word, i+j)))
 		}
 		rows = append(rows, row)
 	}
 
-	rows = append(rows, []tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData("All", fmt.Sprintf("choose-guessed-letters:%s:%d", word, len(word)))})
+	rows = append(rows, []tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData("All", fmt.Sprintf("choose-guessed-letters:%s:%d", word, length))})
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Why?", fmt.Sprintf("why:%s", word)),
+	))
+
+	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	return &markup
+}
+
+func GetMarkupBreakdown(word string) *tgbotapi.InlineKeyboardMarkup {
+	markup := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("« Back", fmt.Sprintf("choose-word:%s", word)),
+		),
+	)
+	return &markup
+}
+
+func GetMarkupPractice(practice *terminal.Practice) *tgbotapi.InlineKeyboardMarkup {
+	tried := make(map[string]struct{})
+	for _, attempt := range practice.History() {
+		tried[attempt.Word] = struct{}{}
+	}
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0)
+	for _, word := range practice.Words() {
+		if _, exists := tried[word]; exists {
+			continue
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(word, fmt.Sprintf("practice-guess:%s", word)),
+		))
+	}
 
 	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
 
 	return &markup
 }
 
+func GetMarkupFinishedGame() *tgbotapi.InlineKeyboardMarkup {
+	markup := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("↶ Undo", "undo"),
+			tgbotapi.NewInlineKeyboardButtonData("Start new game", "start-new-game"),
+		),
+	)
+	return &markup
+}
+
 func GetMarkupNewGame() *tgbotapi.InlineKeyboardMarkup {
 	markup := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
@@ -155,3 +368,41 @@ func GetMarkupNewGame() *tgbotapi.InlineKeyboardMarkup {
 	)
 	return &markup
 }
+
+func GetMarkupStrategies(current terminal.Strategy, minimax bool, probes bool) *tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0)
+
+	for _, strategy := range terminal.Strategies() {
+		label := strategy.Name()
+		if strategy.Name() == current.Name() {
+			label = "• " + label
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("choose-strategy:%s", strategy.Name())),
+		))
+	}
+
+	if minimax {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("• Minimax: on", "choose-minimax:off"),
+		))
+	} else {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Minimax: off", "choose-minimax:on"),
+		))
+	}
+
+	if probes {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("• Probes: on", "choose-probes:off"),
+		))
+	} else {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Probes: off", "choose-probes:on"),
+		))
+	}
+
+	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	return &markup
+}
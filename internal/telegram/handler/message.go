@@ -2,7 +2,6 @@ package handler
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 	"io"
@@ -17,7 +16,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-func (h *Handler) TextMessage(u tgbotapi.Update) {
+func (h *Handler) TextMessage(ctx context.Context, u tgbotapi.Update) {
 	author := u.Message.From
 	log := h.log.With(
 		slog.String("op", "handler.TextMessage"),
@@ -25,17 +24,15 @@ func (h *Handler) TextMessage(u tgbotapi.Update) {
 		slog.String("id", strconv.FormatInt(author.ID, 10)),
 	)
 
-	_, exists := h.stages[author.ID]
-	if !exists {
-		h.stages[author.ID] = None
-	}
-	stage := h.stages[author.ID]
+	stage := h.stages.GetOrSet(author.ID, None)
 
 	switch stage {
 	case WaitingWordList:
-		words := terminal.RemoveTrashFromWordList(strings.Split(u.Message.Text, "\n"))
-
-		game, err := terminal.New(words)
+		game, err := terminal.NewFromTranscript(u.Message.Text, h.gameOptions(ctx, author.ID)...)
+		if errors.Is(err, terminal.ErrInvalidAttempt) {
+			h.sendTextMessage(author.ID, "<b>Each attempt should be a word from the list with amount of guessed letters, like</b> <code>charge 2</code>\n\nSend me list of words in your $TERMINAL game", nil)
+			return
+		}
 		if errors.Is(err, terminal.ErrDifferentWordsLength) {
 			h.sendTextMessage(author.ID, "<b>According to the $TERMINAL rules, the word list should only consist of words of the same length</b>\n\nSend me list of words in your $TERMINAL game", nil)
 			return
@@ -45,29 +42,33 @@ func (h *Handler) TextMessage(u tgbotapi.Update) {
 			return
 		}
 
-		h.games[author.ID] = game
-		h.stages[author.ID] = None
+		h.setGame(author.ID, game)
+		h.stages.Set(author.ID, None)
 
-		answer, err := h.storage.TryFindAnswer(words)
-		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				log.Info("game with sent word list was not found")
-			} else {
-				log.Error("could not get answer from database", sl.Err(err))
-			}
-		}
-		if answer != "" {
-			h.sendTextMessage(author.ID, "<b>Found game with similar words list</b>\n\nProbably, the target is <code>"+answer+"</code>", nil)
+		h.suggestTargets(ctx, log, author.ID, game)
+
+		h.showGameState(ctx, author, 0, game)
+	case WaitingDatasetUser:
+		export, _ := h.datasetExports.Get(author.ID)
+
+		telegramID, err := strconv.ParseInt(strings.TrimSpace(u.Message.Text), 10, 64)
+		if err != nil || telegramID <= 0 {
+			h.sendTextMessage(author.ID, "<b>Telegram ID should be a positive number</b>\n\nSend me telegram ID of the user, whose games should be exported", GetMarkupDataset(export))
+			return
 		}
 
-		h.sendTextMessage(author.ID, fmt.Sprintf("<b>Pick one of %d words in the list</b>", len(words)), GetMarkupWords(h.games[author.ID].AvailableWords()))
+		export.TelegramID = telegramID
+		h.datasetExports.Delete(author.ID)
+		h.stages.Set(author.ID, None)
+
+		h.sendTextMessage(author.ID, getContentDatasetExport(export), GetMarkupDataset(export))
 	case None:
 		h.sendTextMessage(author.ID, "Use /newgame or click the button to start new $TERMINAL game", GetMarkupNewGame())
 	}
 }
 
 // nolint: gocyclo
-func (h *Handler) PhotoMessage(u tgbotapi.Update) {
+func (h *Handler) PhotoMessage(ctx context.Context, u tgbotapi.Update) {
 	author := u.Message.From
 	log := h.log.With(
 		slog.String("op", "handler.PhotoMessage"),
@@ -79,13 +80,9 @@ func (h *Handler) PhotoMessage(u tgbotapi.Update) {
 	sticker, _ := h.sendSticker(author.ID, WaitingSticker)
 	defer h.deleteMessage(author.ID, sticker.MessageID)
 
-	_, exists := h.stages[author.ID]
-	if !exists {
-		h.stages[author.ID] = None
-	}
-	stage := h.stages[author.ID]
+	stage := h.stages.GetOrSet(author.ID, None)
 
-	if stage == None {
+	if stage != WaitingWordList {
 		h.sendTextMessage(author.ID, "Use /newgame or click the button to start new $TERMINAL game", GetMarkupNewGame())
 		return
 	}
@@ -107,7 +104,7 @@ func (h *Handler) PhotoMessage(u tgbotapi.Update) {
 		return
 	}
 
-	words, err := h.ocr.ExtractWords(context.Background(), destination)
+	words, err := h.ocr.ExtractWords(ctx, destination)
 	if err != nil {
 		log.Error("can't read words from image", sl.Err(err))
 		h.sendTextMessage(u.Message.From.ID, "🚨 <b>Can't read words from this image</b>", nil)
@@ -134,7 +131,7 @@ func (h *Handler) PhotoMessage(u tgbotapi.Update) {
 		return
 	}
 
-	game, err := terminal.New(words)
+	game, err := h.newGame(ctx, author.ID, words)
 	if err != nil {
 		content := "<b>Recognized words:</b>\n\n<code>"
 		for _, word := range words {
@@ -152,22 +149,28 @@ func (h *Handler) PhotoMessage(u tgbotapi.Update) {
 		return
 	}
 
-	h.games[author.ID] = game
-	h.stages[author.ID] = None
+	h.setGame(author.ID, game)
+	h.stages.Set(author.ID, None)
+
+	h.suggestTargets(ctx, log, author.ID, game)
+
+	h.sendTextMessage(author.ID, getContentPickWord(game), GetMarkupWords(game))
+}
 
-	answer, err := h.storage.TryFindAnswer(words)
+// suggestTargets sends targets of known games, which word lists are similar to the game one, if they are still available.
+// Exact match of the word list is not required, since OCR could drop or misread a word.
+func (h *Handler) suggestTargets(ctx context.Context, log *slog.Logger, chatID int64, game *terminal.Game) {
+	candidates, err := h.storage.FindCandidates(ctx, game.Words(), game.AvailableWords(), SimilarityThreshold)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			log.Info("game with this word list not found")
-		} else {
-			log.Error("could not get answer from database", sl.Err(err))
-		}
+		log.Error("could not find games with similar word list", sl.Err(err))
+		return
 	}
-	if answer != "" {
-		h.sendTextMessage(author.ID, "<b>Found game with similar words list</b>\n\nProbably, the target is <code>"+answer+"</code>", nil)
+	if len(candidates) == 0 {
+		log.Info("games with similar word list not found")
+		return
 	}
 
-	h.sendTextMessage(author.ID, fmt.Sprintf("<b>Pick one of %d words in the list</b>", len(words)), GetMarkupWords(h.games[author.ID].AvailableWords()))
+	h.sendTextMessage(chatID, getContentCandidates(candidates), nil)
 }
 
 func (h *Handler) downloadFile(file tgbotapi.File) (string, error) {
@@ -1,6 +1,7 @@
 package telegram
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"terminal/internal/ocr"
 	"terminal/internal/storage"
 	"terminal/internal/telegram/handler"
+	"terminal/internal/terminal/dataset"
 	"terminal/pkg/log/sl"
 	"terminal/pkg/str"
 
@@ -20,7 +22,7 @@ type Bot struct {
 	handler *handler.Handler
 }
 
-func New(log *slog.Logger, conf config.Telegram, st storage.Storage, o *ocr.Client) *Bot {
+func New(log *slog.Logger, conf config.Telegram, st storage.Storage, o *ocr.Client, datasetConf config.Dataset) *Bot {
 	client, err := tgbotapi.NewBotAPI(conf.Token)
 	if err != nil {
 		log.Error("failed to start the bot", sl.Err(err))
@@ -28,13 +30,18 @@ func New(log *slog.Logger, conf config.Telegram, st storage.Storage, o *ocr.Clie
 	}
 
 	return &Bot{
-		log:     log,
-		client:  client,
-		handler: handler.New(log, client, st, o),
+		log:    log,
+		client: client,
+		handler: handler.New(log, client, st, o, dataset.Anonymization{
+			Salt:        datasetConf.Salt,
+			CoarsenTime: !datasetConf.ExactTime,
+			MinGames:    datasetConf.MinGames,
+		}),
 	}
 }
 
-func (b *Bot) Run() {
+// Run handles updates until ctx is done. Contexts of updates, being handled, are derived from ctx.
+func (b *Bot) Run(ctx context.Context) {
 	b.log.Info("bot authorized into telegram API", slog.String("username", b.client.Self.UserName))
 
 	u := tgbotapi.NewUpdate(0)
@@ -42,67 +49,105 @@ func (b *Bot) Run() {
 
 	updates := b.client.GetUpdatesChan(u)
 
+	go func() {
+		<-ctx.Done()
+		b.client.StopReceivingUpdates()
+	}()
+
 	for update := range updates {
-		go b.handleUpdate(update)
+		go b.handleUpdate(ctx, update)
 	}
 }
 
-func (b *Bot) handleUpdate(u tgbotapi.Update) {
+func (b *Bot) handleUpdate(ctx context.Context, u tgbotapi.Update) {
 	log := b.log.With(
 		slog.String("op", "bot.handleUpdate"),
 	)
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if from := u.SentFrom(); from != nil {
+		unlock := b.handler.Lock(from.ID)
+		defer unlock()
+	}
+
 	if u.Message != nil {
 		if u.Message.Photo != nil {
 			log.Info("photo message received", slog.Int64("id", u.Message.From.ID), slog.String("username", u.Message.From.UserName))
 
-			b.handler.PhotoMessage(u)
+			b.handler.PhotoMessage(ctx, u)
 			return
 		}
 
 		log.Info("text message received", slog.String("content", str.Unescape(u.Message.Text)), slog.Int64("id", u.Message.From.ID), slog.String("username", u.Message.From.UserName))
 
-		commandHandlers := map[string]func(tgbotapi.Update){
-			"/start":   b.handler.CommandStart,
-			"/newgame": b.handler.CommandGame,
-			"/a":       b.handler.CommandAdmin,
+		commandHandlers := map[string]func(context.Context, tgbotapi.Update){
+			"/start":    b.handler.CommandStart,
+			"/newgame":  b.handler.CommandGame,
+			"/strategy": b.handler.CommandStrategy,
+			"/undo":     b.handler.CommandUndo,
+			"/practice": b.handler.CommandPractice,
+			"/a":        b.handler.CommandAdmin,
 		}
 
 		handler, exists := commandHandlers[u.Message.Text]
 		if exists {
-			handler(u)
+			handler(ctx, u)
 			return
 		}
 
-		b.handler.TextMessage(u)
+		b.handler.TextMessage(ctx, u)
 		return
 	}
 	if u.CallbackQuery != nil {
 		query := u.CallbackData()
 		log.Info("callback received", slog.String("query", query), slog.Int64("id", u.CallbackQuery.From.ID), slog.String("username", u.CallbackQuery.From.UserName))
 
-		callbackHandlers := map[string]func(tgbotapi.Update){
+		callbackHandlers := map[string]func(context.Context, tgbotapi.Update){
 			"game-continue":  b.handler.CallbackContinueGame,
 			"start-new-game": b.handler.CallbackStartNewGame,
 			"words-list":     b.handler.CallbackWordsList,
 			"dataset":        b.handler.CallbackDataset,
 			"admin-panel":    b.handler.CallbackAdminPanel,
 			"stats":          b.handler.CallbackStats,
+			"undo":           b.handler.CallbackUndo,
+			"audit":          b.handler.CallbackAudit,
 		}
 
 		handler, exists := callbackHandlers[query]
 		if exists {
-			handler(u)
+			handler(ctx, u)
 			return
 		}
 
 		switch {
 		case strings.HasPrefix(query, "daily-report:"):
-			b.handler.CallbackDailyReport(u)
+			b.handler.CallbackDailyReport(ctx, u)
 		case strings.HasPrefix(query, "choose-word:"):
-			b.handler.CallbackChooseWord(u)
+			b.handler.CallbackChooseWord(ctx, u)
 		case strings.HasPrefix(query, "choose-guessed-letters:"):
-			b.handler.CallbackChooseGuessedLetters(u)
+			b.handler.CallbackChooseGuessedLetters(ctx, u)
+		case strings.HasPrefix(query, "practice-guess:"):
+			b.handler.CallbackPracticeGuess(ctx, u)
+		case strings.HasPrefix(query, "dataset:"):
+			b.handler.CallbackDataset(ctx, u)
+		case strings.HasPrefix(query, "dataset-export:"):
+			b.handler.CallbackDatasetExport(ctx, u)
+		case strings.HasPrefix(query, "dataset-user:"):
+			b.handler.CallbackDatasetUser(ctx, u)
+		case strings.HasPrefix(query, "audit:"):
+			b.handler.CallbackAudit(ctx, u)
+		case strings.HasPrefix(query, "why:"):
+			b.handler.CallbackWhy(ctx, u)
+		case strings.HasPrefix(query, "amend:"):
+			b.handler.CallbackAmendAttempts(ctx, u)
+		case strings.HasPrefix(query, "choose-strategy:"):
+			b.handler.CallbackChooseStrategy(ctx, u)
+		case strings.HasPrefix(query, "choose-minimax:"):
+			b.handler.CallbackChooseMinimax(ctx, u)
+		case strings.HasPrefix(query, "choose-probes:"):
+			b.handler.CallbackChooseProbes(ctx, u)
 		}
 	}
 }
@@ -0,0 +1,99 @@
+package terminal
+
+// DefaultAttemptBudget is amount of attempts, given in $TERMINAL game.
+const DefaultAttemptBudget = 4
+
+// WithAttemptBudget sets amount of attempts, the target should be found within.
+func WithAttemptBudget(budget int) Option {
+	return func(g *Game) {
+		g.budget = budget
+	}
+}
+
+// AttemptBudget returns amount of attempts, the target should be found within.
+func (g *Game) AttemptBudget() int {
+	return g.budget
+}
+
+// AttemptsLeft returns amount of attempts, that left to find the target, including the winning one.
+func (g *Game) AttemptsLeft() int {
+	left := g.budget - (g.Attempts() - 1)
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// Forecast describes chances to find the target within attempts left.
+type Forecast struct {
+	// SuccessProbability is probability to find the target within attempts left,
+	// if the first suggested word is submitted each time.
+	SuccessProbability float64
+	// Guaranteed reports whether the target could be found within attempts left for sure.
+	Guaranteed bool
+}
+
+// Forecast simulates the game once for each available target, and returns both the success probability
+// and the guarantee. In minimax mode the target is guaranteed to be found, when the solution fits
+// into attempts left, since the first word is the solution one then.
+func (g *Game) Forecast() Forecast {
+	left := g.AttemptsLeft()
+
+	solution, solved := g.Solution()
+	if solved && solution.Depth <= left {
+		return Forecast{
+			SuccessProbability: 1,
+			Guaranteed:         true,
+		}
+	}
+
+	// without the solution the guarantee relies on the current strategy
+	forecast := Forecast{Guaranteed: !solved}
+	for _, target := range g.availableWords {
+		if g.simulate(target) <= left {
+			forecast.SuccessProbability += g.Probability(target)
+		} else {
+			forecast.Guaranteed = false
+		}
+	}
+
+	return forecast
+}
+
+// SuccessProbability returns probability to find the target within attempts left. See Forecast.
+func (g *Game) SuccessProbability() float64 {
+	return g.Forecast().SuccessProbability
+}
+
+// IsGuaranteed reports whether the target could be found within attempts left for sure. See Forecast.
+func (g *Game) IsGuaranteed() bool {
+	return g.Forecast().Guaranteed
+}
+
+// simulate returns amount of attempts to find the target from the current state,
+// if the first suggested word is submitted each time.
+func (g *Game) simulate(target string) int {
+	game := &Game{
+		initialWords:   g.initialWords,
+		availableWords: make([]string, len(g.availableWords)),
+		attempts:       make([]Attempt, len(g.attempts)),
+		strategy:       g.strategy,
+		priors:         g.priors,
+		budget:         g.budget,
+		matrix:         g.matrix,
+	}
+	copy(game.availableWords, g.availableWords)
+	copy(game.attempts, g.attempts)
+
+	n := 0
+	for len(game.availableWords) != 0 {
+		guess := game.availableWords[0]
+		n++
+		if guess == target {
+			return n
+		}
+		game.SubmitAttempt(guess, g.matrix.likeness(guess, target))
+	}
+
+	return n
+}
@@ -0,0 +1,104 @@
+package terminal
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSuccessProbability(t *testing.T) {
+	words := []string{"stone", "shore", "stove", "score", "spoke", "smoke"}
+
+	tests := []struct {
+		budget     int
+		want       float64
+		guaranteed bool
+	}{
+		{budget: 1, want: 1.0 / 6, guaranteed: false},
+		{budget: 6, want: 1, guaranteed: true},
+	}
+
+	for _, tt := range tests {
+		game, err := New(slices.Clone(words), WithAttemptBudget(tt.budget))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if left := game.AttemptsLeft(); left != tt.budget {
+			t.Fatalf("budget %d: attempts left = %d, want %d", tt.budget, left, tt.budget)
+		}
+		if probability := game.SuccessProbability(); math.Abs(probability-tt.want) > 1e-9 {
+			t.Fatalf("budget %d: success probability = %v, want %v", tt.budget, probability, tt.want)
+		}
+		if guaranteed := game.IsGuaranteed(); guaranteed != tt.guaranteed {
+			t.Fatalf("budget %d: guaranteed = %t, want %t", tt.budget, guaranteed, tt.guaranteed)
+		}
+	}
+}
+
+func TestAttemptsLeft(t *testing.T) {
+	game, err := New([]string{"stone", "shore", "stove", "score", "spoke", "smoke"}, WithAttemptBudget(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game.SubmitAttempt("smoke", 3)
+	if left := game.AttemptsLeft(); left != 1 {
+		t.Fatalf("attempts left = %d, want 1", left)
+	}
+	game.SubmitAttempt("shore", 3)
+	if left := game.AttemptsLeft(); left != 0 {
+		t.Fatalf("attempts left = %d, want 0", left)
+	}
+	if probability := game.SuccessProbability(); probability != 0 {
+		t.Fatalf("success probability without attempts left = %v, want 0", probability)
+	}
+}
+
+func TestIsGuaranteedWithMinimax(t *testing.T) {
+	words := randomWords(rand.New(rand.NewSource(8)), 12, 4)
+
+	game, err := New(slices.Clone(words), WithMinimax(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution, ok := game.Solution()
+	if !ok {
+		t.Fatal("no solution in minimax mode")
+	}
+
+	for _, budget := range []int{solution.Depth - 1, solution.Depth} {
+		game, err := New(slices.Clone(words), WithMinimax(time.Minute), WithAttemptBudget(budget))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := budget >= solution.Depth
+		if guaranteed := game.IsGuaranteed(); guaranteed != want {
+			t.Fatalf("budget %d of depth %d: guaranteed = %t, want %t", budget, solution.Depth, guaranteed, want)
+		}
+		if probability := game.SuccessProbability(); want && probability != 1 {
+			t.Fatalf("budget %d of depth %d: success probability = %v, want 1", budget, solution.Depth, probability)
+		}
+	}
+}
+
+func TestIsGuaranteedMatchesSuccessProbability(t *testing.T) {
+	random := rand.New(rand.NewSource(9))
+
+	for i := 0; i < 100; i++ {
+		words := randomWords(random, 6+random.Intn(20), 5)
+
+		game, err := New(words, WithAttemptBudget(2+random.Intn(3)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		certain := math.Abs(game.SuccessProbability()-1) < 1e-9
+		if guaranteed := game.IsGuaranteed(); guaranteed != certain {
+			t.Fatalf("game %d: guaranteed = %t, success probability = %v", i, guaranteed, game.SuccessProbability())
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package dataset
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var ErrMissingSalt = errors.New("dataset.Anonymize(): salt is required to pseudonymise users")
+
+// Anonymization describes how to hide identities of users in exported games.
+type Anonymization struct {
+	// Salt is a secret key of pseudonyms. Exports with the same salt have the same pseudonyms for the same user.
+	Salt string
+	// CoarsenTime truncates games creation time to the day.
+	CoarsenTime bool
+	// MinGames drops games of users, who have less games in the export.
+	MinGames int
+}
+
+// Pseudonym returns a stable pseudonymous user for the telegram ID.
+func (a Anonymization) Pseudonym(telegramID int64) User {
+	mac := hmac.New(sha256.New, []byte(a.Salt))
+	mac.Write([]byte(strconv.FormatInt(telegramID, 10)))
+	sum := mac.Sum(nil)
+
+	return User{
+		// sign bit is cleared, so pseudonymous IDs are positive as real ones
+		TelegramID: int64(binary.BigEndian.Uint64(sum[:8]) >> 1),
+		// username is unique in storage, so it takes as many bits as the ID, to not collide on import
+		Username: "user-" + hex.EncodeToString(sum[:8]),
+	}
+}
+
+// Apply returns the game with pseudonymous user and, optionally, coarsened creation time.
+func (a Anonymization) Apply(game Game) Game {
+	game.User = a.Pseudonym(game.User.TelegramID)
+	if a.CoarsenTime {
+		game.CreatedAt = game.CreatedAt.UTC().Truncate(24 * time.Hour)
+	}
+	return game
+}
+
+// CountGames returns amount of games of each user in the stream. key: telegram ID.
+func CountGames(stream func(func(Game) error) error) (map[int64]int, error) {
+	counts := make(map[int64]int)
+	err := stream(func(game Game) error {
+		counts[game.User.TelegramID]++
+		return nil
+	})
+	return counts, err
+}
+
+// Anonymize returns a Writer, that anonymises games before writing them to w.
+// Counts are amounts of games of each user in the export, as returned by CountGames.
+func Anonymize(w Writer, a Anonymization, counts map[int64]int) (Writer, error) {
+	if a.Salt == "" {
+		return nil, ErrMissingSalt
+	}
+
+	return &anonymousWriter{
+		writer:        w,
+		anonymization: a,
+		counts:        counts,
+	}, nil
+}
+
+type anonymousWriter struct {
+	writer        Writer
+	anonymization Anonymization
+	counts        map[int64]int
+}
+
+func (a *anonymousWriter) Write(game Game) error {
+	if a.counts[game.User.TelegramID] < a.anonymization.MinGames {
+		return nil
+	}
+	return a.writer.Write(a.anonymization.Apply(game))
+}
+
+func (a *anonymousWriter) Close() error {
+	return a.writer.Close()
+}
@@ -1,9 +1,8 @@
 package dataset
 
 import (
-	"encoding/json"
-	"os"
 	"time"
+	"unicode/utf8"
 )
 
 type Dataset struct {
@@ -18,6 +17,12 @@ type Game struct {
 	User           User      `json:"user"`
 	WordsHash      string    `json:"words_hash"`
 	CreatedAt      time.Time `json:"created_at"`
+	Attempts       []Attempt `json:"attempts,omitempty"`
+}
+
+type Attempt struct {
+	Word           string `json:"word"`
+	GuessedLetters int    `json:"guessed_letters"`
 }
 
 type User struct {
@@ -25,17 +30,32 @@ type User struct {
 	Username   string `json:"username"`
 }
 
-func ExportDatasetToJSON(data *Dataset) (string, error) {
-	jsonData, err := json.MarshalIndent(data, "", "    ")
-	if err != nil {
-		return "", err
-	}
+// Filter selects games to export. Zero value of each field disables the corresponding condition.
+type Filter struct {
+	// From and To limit games creation time to [From, To) range.
+	From       time.Time
+	To         time.Time
+	TelegramID int64
+	WordLength int
+	MinWords   int
+}
 
-	path := time.Now().Format("dataset-02-01-2006.json")
-	err = os.WriteFile(path, jsonData, 0600)
-	if err != nil {
-		return "", err
+// Match reports whether the game satisfies all conditions of the filter.
+func (f Filter) Match(game Game) bool {
+	if !f.From.IsZero() && game.CreatedAt.Before(f.From) {
+		return false
 	}
-
-	return path, nil
+	if !f.To.IsZero() && !game.CreatedAt.Before(f.To) {
+		return false
+	}
+	if f.TelegramID != 0 && game.User.TelegramID != f.TelegramID {
+		return false
+	}
+	if f.WordLength != 0 && (len(game.Words) == 0 || utf8.RuneCountInString(game.Words[0]) != f.WordLength) {
+		return false
+	}
+	if f.MinWords != 0 && len(game.Words) < f.MinWords {
+		return false
+	}
+	return true
 }
@@ -0,0 +1,167 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrUnknownFormat = errors.New("dataset.NewWriter(): unknown export format")
+
+// Format is a format of dataset export.
+type Format string
+
+const (
+	// FormatJSON is a single JSON document, compatible with Dataset structure.
+	FormatJSON Format = "json"
+	// FormatNDJSON is one JSON encoded Game per line.
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV is one game per row, words are separated by spaces, attempts are written as `word=likeness`.
+	FormatCSV Format = "csv"
+)
+
+// Formats returns all supported export formats.
+func Formats() []Format {
+	return []Format{FormatJSON, FormatNDJSON, FormatCSV}
+}
+
+// ParseFormat returns the format with provided name.
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats() {
+		if string(format) == strings.ToLower(name) {
+			return format, nil
+		}
+	}
+	return "", ErrUnknownFormat
+}
+
+// Extension returns file extension for the format.
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// Writer writes games one by one, without keeping them in memory.
+type Writer interface {
+	Write(game Game) error
+	// Close finishes the export. It doesn't close the underlying io.Writer.
+	Close() error
+}
+
+// NewWriter returns a Writer, that streams games to w in the format.
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonWriter{w: w}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}, nil
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+// Export writes games, produced by the stream, to w in the format.
+func Export(w io.Writer, format Format, stream func(func(Game) error) error) error {
+	writer, err := NewWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	if err = stream(writer.Write); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+type jsonWriter struct {
+	w     io.Writer
+	total int
+}
+
+func (j *jsonWriter) Write(game Game) error {
+	data, err := json.MarshalIndent(game, "        ", "    ")
+	if err != nil {
+		return err
+	}
+
+	prefix := ",\n        "
+	if j.total == 0 {
+		prefix = "{\n    \"games\": [\n        "
+	}
+	j.total++
+
+	_, err = fmt.Fprintf(j.w, "%s%s", prefix, data)
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	var err error
+	if j.total == 0 {
+		_, err = fmt.Fprintf(j.w, "{\n    \"games\": [],\n    \"total_games\": 0\n}\n")
+	} else {
+		_, err = fmt.Fprintf(j.w, "\n    ],\n    \"total_games\": %d\n}\n", j.total)
+	}
+	return err
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(game Game) error {
+	return n.encoder.Encode(game)
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
+
+// csvHeader is the first row of CSV export.
+var csvHeader = []string{"created_at", "telegram_id", "username", "words_hash", "target", "attempts_amount", "words", "attempts"}
+
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (c *csvWriter) Write(game Game) error {
+	if !c.header {
+		if err := c.w.Write(csvHeader); err != nil {
+			return err
+		}
+		c.header = true
+	}
+
+	attempts := make([]string, len(game.Attempts))
+	for i, attempt := range game.Attempts {
+		attempts[i] = fmt.Sprintf("%s=%d", attempt.Word, attempt.GuessedLetters)
+	}
+
+	return c.w.Write([]string{
+		game.CreatedAt.Format(time.RFC3339Nano),
+		strconv.FormatInt(game.User.TelegramID, 10),
+		game.User.Username,
+		game.WordsHash,
+		game.Target,
+		strconv.Itoa(game.AttemptsAmount),
+		strings.Join(game.Words, " "),
+		strings.Join(attempts, " "),
+	})
+}
+
+func (c *csvWriter) Close() error {
+	if !c.header {
+		if err := c.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,125 @@
+package dataset
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRecord = errors.New("dataset.Read(): invalid record")
+
+// Read decodes the dataset, exported in the format.
+func Read(r io.Reader, format Format) (*Dataset, error) {
+	var games []Game
+	var err error
+
+	switch format {
+	case FormatJSON:
+		var data Dataset
+		err = json.NewDecoder(r).Decode(&data)
+		games = data.Games
+	case FormatNDJSON:
+		games, err = readNDJSON(r)
+	case FormatCSV:
+		games, err = readCSV(r)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dataset{
+		TotalGames: len(games),
+		Games:      games,
+	}, nil
+}
+
+func readNDJSON(r io.Reader) ([]Game, error) {
+	games := make([]Game, 0)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var game Game
+		if err := json.Unmarshal(scanner.Bytes(), &game); err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+
+	return games, scanner.Err()
+}
+
+func readCSV(r io.Reader) ([]Game, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // exports before attempts were recorded have no attempts column
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	games := make([]Game, 0)
+	for i, record := range records {
+		if i == 0 {
+			continue // header
+		}
+		if len(record) != len(csvHeader) && len(record) != len(csvHeader)-1 {
+			return nil, fmt.Errorf("%w: line %d", ErrInvalidRecord, i+1)
+		}
+
+		createdAt, err := time.Parse(time.RFC3339Nano, record[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %s", ErrInvalidRecord, i+1, err)
+		}
+		telegramID, err := strconv.ParseInt(record[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %s", ErrInvalidRecord, i+1, err)
+		}
+		attemptsAmount, err := strconv.Atoi(record[5])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %s", ErrInvalidRecord, i+1, err)
+		}
+
+		var attempts []Attempt
+		if len(record) == len(csvHeader) {
+			for _, field := range strings.Fields(record[7]) {
+				word, likeness, found := strings.Cut(field, "=")
+				guessedLetters, err := strconv.Atoi(likeness)
+				if !found || err != nil {
+					return nil, fmt.Errorf("%w: line %d: invalid attempt %s", ErrInvalidRecord, i+1, field)
+				}
+				attempts = append(attempts, Attempt{
+					Word:           word,
+					GuessedLetters: guessedLetters,
+				})
+			}
+		}
+
+		games = append(games, Game{
+			Words:          strings.Fields(record[6]),
+			Target:         record[4],
+			AttemptsAmount: attemptsAmount,
+			User: User{
+				TelegramID: telegramID,
+				Username:   record[2],
+			},
+			WordsHash: record[3],
+			CreatedAt: createdAt,
+			Attempts:  attempts,
+		})
+	}
+
+	return games, nil
+}
@@ -0,0 +1,125 @@
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidCorrection = errors.New("terminal.Game.Amend(): invalid correction")
+
+// Correction is a guessed letters value for the attempt from the history, that differs from submitted one.
+type Correction struct {
+	Attempt        int
+	Word           string
+	GuessedLetters int
+}
+
+// Diagnose analyses history of the game, that has no available words left, and returns all minimal
+// sets of corrections, each of them makes the history consistent again.
+func (g *Game) Diagnose() [][]Correction {
+	if len(g.availableWords) != 0 {
+		return nil
+	}
+
+	for size := 1; size <= len(g.attempts); size++ {
+		fixes := make([][]Correction, 0)
+		seen := make(map[string]struct{})
+
+		forEachCombination(len(g.attempts), size, func(changed []int) {
+			for _, word := range g.initialWords {
+				corrections, ok := g.correctionsFor(word, changed)
+				if !ok {
+					continue
+				}
+
+				key := correctionsKey(corrections)
+				if _, exists := seen[key]; exists {
+					continue
+				}
+				seen[key] = struct{}{}
+				fixes = append(fixes, corrections)
+			}
+		})
+
+		if len(fixes) != 0 {
+			return fixes
+		}
+	}
+
+	return nil
+}
+
+// Amend replaces guessed letters of the attempts from the history and recomputes available words.
+func (g *Game) Amend(corrections ...Correction) error {
+	for _, correction := range corrections {
+		if correction.Attempt < 0 || correction.Attempt >= len(g.attempts) {
+			return ErrInvalidCorrection
+		}
+		if correction.GuessedLetters < 0 || correction.GuessedLetters > g.wordLength() {
+			return ErrInvalidCorrection
+		}
+	}
+
+	for _, correction := range corrections {
+		g.attempts[correction.Attempt].GuessedLetters = correction.GuessedLetters
+	}
+	g.replay()
+
+	return nil
+}
+
+// correctionsFor checks, if word could be the target, when only attempts with changed indexes were submitted wrong.
+func (g *Game) correctionsFor(word string, changed []int) ([]Correction, bool) {
+	corrections := make([]Correction, 0, len(changed))
+
+	next := 0
+	for i, attempt := range g.attempts {
+		matched := g.matrix.likeness(word, attempt.Word)
+
+		if next < len(changed) && changed[next] == i {
+			next++
+			if matched == attempt.GuessedLetters {
+				return nil, false
+			}
+			corrections = append(corrections, Correction{
+				Attempt:        i,
+				Word:           attempt.Word,
+				GuessedLetters: matched,
+			})
+			continue
+		}
+
+		if matched != attempt.GuessedLetters {
+			return nil, false
+		}
+	}
+
+	return corrections, true
+}
+
+func forEachCombination(n, k int, fn func([]int)) {
+	combination := make([]int, k)
+
+	var walk func(start, depth int)
+	walk = func(start, depth int) {
+		if depth == k {
+			fn(combination)
+			return
+		}
+		for i := start; i <= n-(k-depth); i++ {
+			combination[depth] = i
+			walk(i+1, depth+1)
+		}
+	}
+
+	walk(0, 0)
+}
+
+func correctionsKey(corrections []Correction) string {
+	var builder strings.Builder
+	for _, correction := range corrections {
+		builder.WriteString(fmt.Sprintf("%d=%d;", correction.Attempt, correction.GuessedLetters))
+	}
+	return builder.String()
+}
@@ -0,0 +1,96 @@
+package terminal
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestDiagnoseSingleWrongLikeness(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+
+	diagnosed := 0
+	for i := 0; diagnosed < 50 && i < 1000; i++ {
+		words := randomWords(random, 10+random.Intn(20), 5)
+		target := words[random.Intn(len(words))]
+
+		history, err := Simulate(words, target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(history) < 2 {
+			continue
+		}
+
+		game, err := New(slices.Clone(words))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wrong := random.Intn(len(history) - 1)
+		for j, attempt := range history {
+			guessed := attempt.GuessedLetters
+			if j == wrong {
+				guessed = (guessed + 1 + random.Intn(game.wordLength())) % (game.wordLength() + 1)
+			}
+			game.SubmitAttempt(attempt.Word, guessed)
+		}
+		if len(game.AvailableWords()) != 0 {
+			continue // the wrong likeness still fits some word
+		}
+		diagnosed++
+
+		fix := Correction{Attempt: wrong, Word: history[wrong].Word, GuessedLetters: history[wrong].GuessedLetters}
+
+		fixes := game.Diagnose()
+		found := false
+		for _, corrections := range fixes {
+			if len(corrections) != 1 {
+				t.Fatalf("game %d: corrections %v are not minimal, single wrong attempt could be fixed", i, corrections)
+			}
+			if corrections[0] == fix {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("game %d: corrections %v miss %v", i, fixes, fix)
+		}
+
+		if err = game.Amend(fix); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Contains(game.AvailableWords(), target) {
+			t.Fatalf("game %d: available words %v after amend miss the target %s", i, game.AvailableWords(), target)
+		}
+		if game.Diagnose() != nil {
+			t.Fatalf("game %d: amended game is still inconsistent", i)
+		}
+	}
+
+	if diagnosed == 0 {
+		t.Fatal("no inconsistent games were diagnosed")
+	}
+}
+
+func TestAmendInvalidCorrection(t *testing.T) {
+	game, err := New([]string{"stone", "shore", "stove", "score", "spoke", "smoke"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	game.SubmitAttempt("smoke", 3)
+
+	tests := []Correction{
+		{Attempt: 1, GuessedLetters: 2},
+		{Attempt: -1, GuessedLetters: 2},
+		{Attempt: 0, GuessedLetters: 6},
+		{Attempt: 0, GuessedLetters: -1},
+	}
+	for _, correction := range tests {
+		if err := game.Amend(correction); err != ErrInvalidCorrection {
+			t.Fatalf("Amend(%+v): err = %v, want %v", correction, err, ErrInvalidCorrection)
+		}
+	}
+	if history := game.History(); history[0].GuessedLetters != 3 {
+		t.Fatalf("invalid correction changed the history: %v", history)
+	}
+}
@@ -0,0 +1,113 @@
+package terminal
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// GameVersion is a version of the Game wire format. It should be increased on any
+// incompatible change of the format.
+const GameVersion = 1
+
+var ErrUnsupportedVersion = errors.New("terminal.Game.UnmarshalJSON(): unsupported game version")
+
+type gameJSON struct {
+	Version  int                `json:"version"`
+	Words    []string           `json:"words"`
+	Attempts []attemptJSON      `json:"attempts"`
+	Strategy string             `json:"strategy"`
+	Minimax  int64              `json:"minimax_ms,omitempty"`
+	Budget   int                `json:"attempt_budget,omitempty"`
+	Probes   bool               `json:"probes,omitempty"`
+	Priors   map[string]float64 `json:"priors,omitempty"`
+}
+
+type attemptJSON struct {
+	Word           string `json:"word"`
+	GuessedLetters int    `json:"guessed_letters"`
+}
+
+// MarshalJSON encodes the game with its initial words, attempts history and options.
+func (g *Game) MarshalJSON() ([]byte, error) {
+	data := gameJSON{
+		Version:  GameVersion,
+		Words:    g.initialWords,
+		Attempts: make([]attemptJSON, 0, len(g.attempts)),
+		Strategy: g.strategy.Name(),
+		Minimax:  g.minimax.Milliseconds(),
+		Budget:   g.budget,
+		Probes:   g.probes,
+	}
+
+	for _, attempt := range g.attempts {
+		data.Attempts = append(data.Attempts, attemptJSON{
+			Word:           attempt.Word,
+			GuessedLetters: attempt.GuessedLetters,
+		})
+	}
+
+	if g.priors != nil {
+		data.Priors = make(map[string]float64)
+		for _, word := range g.initialWords {
+			if weight, exists := g.priors[word]; exists {
+				data.Priors[word] = weight
+			}
+		}
+	}
+
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON restores the game, encoded by MarshalJSON, and replays its attempts.
+func (g *Game) UnmarshalJSON(b []byte) error {
+	var data gameJSON
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	if data.Version != GameVersion {
+		return ErrUnsupportedVersion
+	}
+
+	strategy, err := StrategyByName(data.Strategy)
+	if err != nil {
+		return err
+	}
+
+	opts := []Option{WithStrategy(strategy)}
+	if data.Budget > 0 {
+		opts = append(opts, WithAttemptBudget(data.Budget))
+	}
+	if data.Minimax > 0 {
+		opts = append(opts, WithMinimax(time.Duration(data.Minimax)*time.Millisecond))
+	}
+	if data.Probes {
+		opts = append(opts, WithProbes())
+	}
+	if data.Priors != nil {
+		opts = append(opts, WithPriors(Priors(data.Priors)))
+	}
+
+	// words are ranked once, after all attempts are replayed, since ranking could run the game-tree search
+	game, err := newGame(data.Words, opts...)
+	if err != nil {
+		return err
+	}
+
+	for _, encoded := range data.Attempts {
+		attempt := Attempt{
+			Word:           normalizeWord(encoded.Word),
+			GuessedLetters: encoded.GuessedLetters,
+		}
+		if !game.isValidAttempt(attempt) {
+			return ErrInvalidAttempt
+		}
+		game.attempts = append(game.attempts, attempt)
+	}
+	game.replay()
+
+	*g = *game
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package terminal
+
+import (
+	"encoding/json"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestGameJSONRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+
+	for i := 0; i < 100; i++ {
+		words := randomWords(random, 10+random.Intn(30), 6)
+		target := words[random.Intn(len(words))]
+
+		priors := make(Priors)
+		for _, word := range words[:len(words)/2] {
+			priors[word] = random.Float64()
+		}
+
+		strategy := Strategies()[i%len(Strategies())]
+		game, err := New(words, WithStrategy(strategy), WithPriors(priors), WithAttemptBudget(5), WithProbes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < 2 && len(game.AvailableWords()) > 1; j++ {
+			guess := game.AvailableWords()[0]
+			game.SubmitAttempt(guess, game.matrix.likeness(guess, target))
+		}
+
+		b, err := json.Marshal(game)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var restored Game
+		if err := json.Unmarshal(b, &restored); err != nil {
+			t.Fatal(err)
+		}
+
+		if !slices.Equal(game.Words(), restored.Words()) {
+			t.Fatalf("game %d: words = %v, want %v", i, restored.Words(), game.Words())
+		}
+		if !slices.Equal(game.History(), restored.History()) {
+			t.Fatalf("game %d: attempts = %v, want %v", i, restored.History(), game.History())
+		}
+		if restored.Strategy() != game.Strategy() {
+			t.Fatalf("game %d: strategy = %s, want %s", i, restored.Strategy().Name(), game.Strategy().Name())
+		}
+		if restored.AttemptBudget() != game.AttemptBudget() {
+			t.Fatalf("game %d: attempt budget = %d, want %d", i, restored.AttemptBudget(), game.AttemptBudget())
+		}
+		if !slices.Equal(game.AvailableWords(), restored.AvailableWords()) {
+			t.Fatalf("game %d: available words = %v, want %v", i, restored.AvailableWords(), game.AvailableWords())
+		}
+
+		probe, _ := game.Probe()
+		restoredProbe, _ := restored.Probe()
+		if probe != restoredProbe {
+			t.Fatalf("game %d: probe = %q, want %q", i, restoredProbe, probe)
+		}
+	}
+}
+
+func TestGameJSONInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  error
+	}{
+		{
+			name: "unsupported version",
+			data: `{"version":2,"words":["aaaaa","aaaab","aaabb","aabbb","abbbb","bbbbb"],"strategy":"sexy-index"}`,
+			err:  ErrUnsupportedVersion,
+		},
+		{
+			name: "unknown strategy",
+			data: `{"version":1,"words":["aaaaa","aaaab","aaabb","aabbb","abbbb","bbbbb"],"strategy":"random"}`,
+			err:  ErrUnknownStrategy,
+		},
+		{
+			name: "unknown attempt word",
+			data: `{"version":1,"words":["aaaaa","aaaab","aaabb","aabbb","abbbb","bbbbb"],"attempts":[{"word":"ccccc","guessed_letters":1}],"strategy":"sexy-index"}`,
+			err:  ErrInvalidAttempt,
+		},
+		{
+			name: "too many guessed letters",
+			data: `{"version":1,"words":["aaaaa","aaaab","aaabb","aabbb","abbbb","bbbbb"],"attempts":[{"word":"aaaaa","guessed_letters":6}],"strategy":"sexy-index"}`,
+			err:  ErrInvalidAttempt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var game Game
+			if err := json.Unmarshal([]byte(tt.data), &game); err != tt.err {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
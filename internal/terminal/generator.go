@@ -0,0 +1,227 @@
+package terminal
+
+import (
+	_ "embed"
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"terminal/internal/terminal/dataset"
+	"terminal/pkg/slice"
+	"unicode/utf8"
+)
+
+var ErrDictionaryTooSmall = errors.New("terminal.Generator.Generate(): dictionary has not enough words of such length")
+
+//go:embed dictionary.txt
+var embeddedDictionary string
+
+// ListStats describes word lists of recorded games: how often each list size and word length occur,
+// and how many letters the target shares with other words, relative to the word length.
+type ListStats struct {
+	Sizes   map[int]int
+	Lengths map[int]int
+	// Overlap[n] is amount of words in lists, that share n/len of letters with the target,
+	// where the ratio is rounded to tenths.
+	Overlap [11]int
+}
+
+// DefaultListStats is used, when there are no recorded games to derive statistics from.
+var DefaultListStats = ListStats{
+	Sizes:   map[int]int{8: 1, 10: 2, 12: 3, 14: 2, 16: 1},
+	Lengths: map[int]int{5: 1, 6: 2, 7: 2, 8: 1},
+	Overlap: [11]int{10, 20, 25, 20, 12, 7, 4, 2, 0, 0, 0},
+}
+
+// NewListStats derives word lists statistics from the dataset. Games with invalid lists are skipped.
+func NewListStats(data *dataset.Dataset) ListStats {
+	stats := ListStats{
+		Sizes:   make(map[int]int),
+		Lengths: make(map[int]int),
+	}
+
+	for _, game := range data.Games {
+		if len(game.Words) < 6 || !isWordsEqualLength(game.Words) {
+			continue
+		}
+
+		length := utf8.RuneCountInString(game.Words[0])
+		stats.Sizes[len(game.Words)]++
+		stats.Lengths[length]++
+
+		for _, word := range game.Words {
+			if word == game.Target {
+				continue
+			}
+			ratio := float64(countMatchedLetters(word, game.Target)) / float64(length)
+			stats.Overlap[int(math.Round(ratio*10))]++
+		}
+	}
+
+	if len(stats.Sizes) == 0 {
+		return DefaultListStats
+	}
+
+	return stats
+}
+
+// GeneratedList is a synthetic word list with the chosen target.
+type GeneratedList struct {
+	Words  []string
+	Target string
+}
+
+// Generator produces realistic word lists from the dictionary, following statistics of recorded games.
+// Generators with the same seed, statistics and dictionary produce the same lists.
+type Generator struct {
+	random     *rand.Rand
+	stats      ListStats
+	dictionary map[int][]string // key: word length
+}
+
+// NewGenerator creates a generator over the embedded dictionary.
+func NewGenerator(stats ListStats, seed int64) *Generator {
+	return NewGeneratorWithDictionary(strings.Split(embeddedDictionary, "\n"), stats, seed)
+}
+
+// NewGeneratorWithDictionary creates a generator over provided dictionary.
+func NewGeneratorWithDictionary(words []string, stats ListStats, seed int64) *Generator {
+	dictionary := make(map[int][]string)
+	for _, word := range slice.Unique(words) {
+		word = normalizeWord(word)
+		if word == "" {
+			continue
+		}
+		length := utf8.RuneCountInString(word)
+		dictionary[length] = append(dictionary[length], word)
+	}
+	for length := range dictionary {
+		sort.Strings(dictionary[length])
+	}
+
+	return &Generator{
+		random:     rand.New(rand.NewSource(seed)),
+		stats:      stats,
+		dictionary: dictionary,
+	}
+}
+
+// Generate produces a word list with size and word length, picked according to statistics, and random target.
+func (g *Generator) Generate() (GeneratedList, error) {
+	length := g.nearestLength(g.pick(g.stats.Lengths))
+	candidates := g.dictionary[length]
+	if len(candidates) == 0 {
+		return GeneratedList{}, ErrDictionaryTooSmall
+	}
+
+	return g.GenerateFor(candidates[g.random.Intn(len(candidates))])
+}
+
+// GenerateFor produces a word list with the chosen target. Other words are picked from the dictionary,
+// so their likeness with the target follows statistics.
+func (g *Generator) GenerateFor(target string) (GeneratedList, error) {
+	target = normalizeWord(target)
+	length := utf8.RuneCountInString(target)
+	size := g.pick(g.stats.Sizes)
+
+	// group dictionary words by likeness with the target
+	byLikeness := make(map[int][]string)
+	total := 0
+	for _, word := range g.dictionary[length] {
+		if word == target {
+			continue
+		}
+		likeness := countMatchedLetters(word, target)
+		byLikeness[likeness] = append(byLikeness[likeness], word)
+		total++
+	}
+	if total < size-1 {
+		return GeneratedList{}, ErrDictionaryTooSmall
+	}
+
+	words := []string{target}
+	for len(words) < size {
+		likeness := int(math.Round(float64(g.pickOverlap()) / 10 * float64(length)))
+		words = append(words, g.take(byLikeness, likeness, length))
+	}
+
+	g.random.Shuffle(len(words), func(i, j int) {
+		words[i], words[j] = words[j], words[i]
+	})
+
+	return GeneratedList{
+		Words:  words,
+		Target: target,
+	}, nil
+}
+
+// take removes a random word with the likeness from the groups, or with the closest one, if there are no such words.
+func (g *Generator) take(byLikeness map[int][]string, likeness int, length int) string {
+	for distance := 0; distance <= length; distance++ {
+		for _, l := range []int{likeness - distance, likeness + distance} {
+			group := byLikeness[l]
+			if len(group) == 0 {
+				continue
+			}
+
+			i := g.random.Intn(len(group))
+			word := group[i]
+			group[i] = group[len(group)-1]
+			byLikeness[l] = group[:len(group)-1]
+
+			return word
+		}
+	}
+	return ""
+}
+
+// pick returns a random key of the distribution, weighted by its value.
+func (g *Generator) pick(distribution map[int]int) int {
+	keys := make([]int, 0, len(distribution))
+	total := 0
+	for key, weight := range distribution {
+		keys = append(keys, key)
+		total += weight
+	}
+	if total == 0 {
+		return 0
+	}
+	// map iteration order is random, so keys are sorted to keep results reproducible
+	sort.Ints(keys)
+
+	n := g.random.Intn(total)
+	for _, key := range keys {
+		n -= distribution[key]
+		if n < 0 {
+			return key
+		}
+	}
+	return keys[len(keys)-1]
+}
+
+func (g *Generator) pickOverlap() int {
+	distribution := make(map[int]int, len(g.stats.Overlap))
+	for ratio, weight := range g.stats.Overlap {
+		distribution[ratio] = weight
+	}
+	return g.pick(distribution)
+}
+
+// nearestLength returns the closest word length, the dictionary has words of.
+func (g *Generator) nearestLength(length int) int {
+	nearest := 0
+	for l := range g.dictionary {
+		if nearest == 0 || abs(l-length) < abs(nearest-length) || (abs(l-length) == abs(nearest-length) && l < nearest) {
+			nearest = l
+		}
+	}
+	return nearest
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
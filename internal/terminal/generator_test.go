@@ -0,0 +1,86 @@
+package terminal
+
+import (
+	"slices"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGeneratorSameSeed(t *testing.T) {
+	a := NewGenerator(DefaultListStats, 42)
+	b := NewGenerator(DefaultListStats, 42)
+
+	for i := 0; i < 20; i++ {
+		listA, err := a.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		listB, err := b.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if listA.Target != listB.Target || !slices.Equal(listA.Words, listB.Words) {
+			t.Fatalf("list %d: generators with the same seed produced %v and %v", i, listA, listB)
+		}
+	}
+}
+
+func TestGeneratorConstraints(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats ListStats
+	}{
+		{"default", DefaultListStats},
+		{"fixed", ListStats{
+			Sizes:   map[int]int{7: 1},
+			Lengths: map[int]int{6: 1},
+			Overlap: [11]int{0, 0, 1, 1, 1, 0, 0, 0, 0, 0, 0},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := NewGenerator(tt.stats, 1)
+
+			for i := 0; i < 50; i++ {
+				list, err := generator.Generate()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if _, exists := tt.stats.Sizes[len(list.Words)]; !exists {
+					t.Fatalf("list %d: size %d is not in %v", i, len(list.Words), tt.stats.Sizes)
+				}
+				length := utf8.RuneCountInString(list.Target)
+				if _, exists := tt.stats.Lengths[length]; !exists {
+					t.Fatalf("list %d: word length %d is not in %v", i, length, tt.stats.Lengths)
+				}
+				if !isWordsEqualLength(list.Words) {
+					t.Fatalf("list %d: words have different length: %v", i, list.Words)
+				}
+				if !slices.Contains(list.Words, list.Target) {
+					t.Fatalf("list %d: target %s is not in %v", i, list.Target, list.Words)
+				}
+				sorted := slices.Clone(list.Words)
+				slices.Sort(sorted)
+				if unique := slices.Compact(sorted); len(unique) != len(list.Words) {
+					t.Fatalf("list %d: words are not unique: %v", i, list.Words)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratorDictionaryTooSmall(t *testing.T) {
+	stats := ListStats{
+		Sizes:   map[int]int{8: 1},
+		Lengths: map[int]int{5: 1},
+		Overlap: DefaultListStats.Overlap,
+	}
+	generator := NewGeneratorWithDictionary([]string{"stone", "shore", "stove", "score", "spoke"}, stats, 1)
+
+	if _, err := generator.Generate(); err != ErrDictionaryTooSmall {
+		t.Fatalf("err = %v, want %v", err, ErrDictionaryTooSmall)
+	}
+}
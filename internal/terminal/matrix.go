@@ -0,0 +1,54 @@
+package terminal
+
+// likenessMatrix keeps amount of matched letters for each pair of the initial words,
+// so it's computed only once per game.
+type likenessMatrix struct {
+	index  map[string]int
+	values [][]uint8
+}
+
+func newLikenessMatrix(words []string) *likenessMatrix {
+	m := &likenessMatrix{
+		index:  make(map[string]int, len(words)),
+		values: make([][]uint8, len(words)),
+	}
+
+	for i, word := range words {
+		m.index[word] = i
+		m.values[i] = make([]uint8, len(words))
+	}
+
+	for i := range words {
+		m.values[i][i] = uint8(countMatchedLetters(words[i], words[i]))
+		for j := i + 1; j < len(words); j++ {
+			likeness := uint8(countMatchedLetters(words[i], words[j]))
+			m.values[i][j] = likeness
+			m.values[j][i] = likeness
+		}
+	}
+
+	return m
+}
+
+// likeness returns amount of matched letters in words. Words, that are not in the matrix, are compared directly.
+func (m *likenessMatrix) likeness(a, b string) int {
+	i, okA := m.index[a]
+	j, okB := m.index[b]
+	if okA && okB {
+		return int(m.values[i][j])
+	}
+	return countMatchedLetters(a, b)
+}
+
+// indexes returns positions of words in the matrix, or -1 for unknown words.
+func (m *likenessMatrix) indexes(words []string) []int {
+	indexes := make([]int, len(words))
+	for i, word := range words {
+		index, exists := m.index[word]
+		if !exists {
+			index = -1
+		}
+		indexes[i] = index
+	}
+	return indexes
+}
@@ -0,0 +1,68 @@
+package terminal
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+	"unicode/utf8"
+)
+
+func BenchmarkRank(b *testing.B) {
+	for _, size := range []int{20, 50, 100, 200} {
+		game, err := New(randomWords(rand.New(rand.NewSource(int64(size))), size, 8))
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(fmt.Sprintf("pairwise/words=%d", size), func(b *testing.B) {
+			words := make([]string, len(game.availableWords))
+			for i := 0; i < b.N; i++ {
+				copy(words, game.availableWords)
+				sortWordsPairwise(words)
+			}
+		})
+		b.Run(fmt.Sprintf("matrix/words=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				game.sortWords()
+			}
+		})
+	}
+}
+
+// sortWordsPairwise is a ranking without the likeness matrix, that counts matched letters of each pair
+// inside the sort comparator. It's a baseline for the matrix ranking.
+func sortWordsPairwise(words []string) {
+	score := func(guess string) float64 {
+		buckets := make([]int, utf8.RuneCountInString(guess)+1)
+		for _, word := range words {
+			buckets[countMatchedLetters(guess, word)]++
+		}
+		return StrategySexyIndex.Score(Partition{Guess: guess, Buckets: buckets})
+	}
+
+	sort.Slice(words, func(i, j int) bool {
+		return score(words[i]) < score(words[j])
+	})
+}
+
+// randomWords returns unique words over a small alphabet, so they share a lot of letters like in real games.
+func randomWords(random *rand.Rand, size int, length int) []string {
+	const alphabet = "abcdefgh"
+
+	seen := make(map[string]struct{}, size)
+	words := make([]string, 0, size)
+	for len(words) < size {
+		word := make([]byte, length)
+		for i := range word {
+			word[i] = alphabet[random.Intn(len(alphabet))]
+		}
+		if _, exists := seen[string(word)]; exists {
+			continue
+		}
+		seen[string(word)] = struct{}{}
+		words = append(words, string(word))
+	}
+
+	return words
+}
@@ -0,0 +1,99 @@
+package terminal
+
+import (
+	"errors"
+	"slices"
+)
+
+var (
+	ErrPracticeFinished = errors.New("terminal.Practice.Guess(): practice is already finished")
+	ErrUnknownWord      = errors.New("terminal.Practice.Guess(): word is not in the list")
+)
+
+// Practice is a game, where the target is known to the bot, and user guesses it.
+type Practice struct {
+	words    []string
+	target   string
+	budget   int
+	attempts []Attempt
+}
+
+// NewPractice creates a practice game with the hidden target and limited amount of attempts.
+func NewPractice(words []string, target string, budget int) (*Practice, error) {
+	list := make([]string, len(words))
+	for i := range words {
+		list[i] = normalizeWord(words[i])
+	}
+
+	if !isWordsEqualLength(list) {
+		return nil, ErrDifferentWordsLength
+	}
+
+	if len(list) < 6 {
+		return nil, ErrInsufficientWords
+	}
+
+	target = normalizeWord(target)
+	if !slices.Contains(list, target) {
+		return nil, ErrUnknownTarget
+	}
+
+	return &Practice{
+		words:    list,
+		target:   target,
+		budget:   budget,
+		attempts: make([]Attempt, 0),
+	}, nil
+}
+
+func (p *Practice) Words() []string {
+	return p.words
+}
+
+// Target returns the hidden target. It should be revealed only when the practice is finished.
+func (p *Practice) Target() string {
+	return p.target
+}
+
+func (p *Practice) History() []Attempt {
+	history := make([]Attempt, len(p.attempts))
+	copy(history, p.attempts)
+	return history
+}
+
+// Guess submits the word and returns amount of letters, it shares with the target.
+func (p *Practice) Guess(word string) (int, error) {
+	if p.Finished() {
+		return 0, ErrPracticeFinished
+	}
+
+	word = normalizeWord(word)
+	if !slices.Contains(p.words, word) {
+		return 0, ErrUnknownWord
+	}
+
+	guessedLetters := countMatchedLetters(word, p.target)
+	p.attempts = append(p.attempts, Attempt{
+		Word:           word,
+		GuessedLetters: guessedLetters,
+	})
+
+	return guessedLetters, nil
+}
+
+func (p *Practice) AttemptsLeft() int {
+	return p.budget - len(p.attempts)
+}
+
+func (p *Practice) Won() bool {
+	return len(p.attempts) != 0 && p.attempts[len(p.attempts)-1].Word == p.target
+}
+
+func (p *Practice) Finished() bool {
+	return p.Won() || p.AttemptsLeft() <= 0
+}
+
+// SolverPlay returns attempts, the solver would submit to find the target.
+func (p *Practice) SolverPlay(opts ...Option) ([]Attempt, error) {
+	return Simulate(p.words, p.target, opts...)
+}
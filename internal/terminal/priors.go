@@ -0,0 +1,56 @@
+package terminal
+
+// priorsSmoothing is a weight of the uniform prior in target frequency estimation,
+// so words seen in a few games only don't get extreme probabilities.
+const priorsSmoothing = 10
+
+// WordFrequency is amount of games, where the word appeared in the list, and where it was the target.
+type WordFrequency struct {
+	Word        string
+	Appearances int
+	Targets     int
+}
+
+// Priors keeps relative likelihood of each word to be the target, when it appears in the list.
+type Priors map[string]float64
+
+// NewPriors builds priors from words frequencies in recorded games.
+func NewPriors(frequencies []WordFrequency) Priors {
+	priors := make(Priors, len(frequencies))
+	for _, frequency := range frequencies {
+		priors[frequency.Word] = float64(frequency.Targets+1) / float64(frequency.Appearances+priorsSmoothing)
+	}
+	return priors
+}
+
+func (p Priors) weight(word string) float64 {
+	if weight, exists := p[word]; exists {
+		return weight
+	}
+	return 1.0 / priorsSmoothing
+}
+
+// WithPriors makes the game weight candidates by their likelihood to be the target.
+// Without priors all available words are considered equally likely.
+func WithPriors(priors Priors) Option {
+	return func(g *Game) {
+		g.priors = priors
+	}
+}
+
+// Probability returns probability of the word to be the target among available words.
+func (g *Game) Probability(word string) float64 {
+	total := 0.0
+	found := false
+	for _, candidate := range g.availableWords {
+		total += g.priors.weight(candidate)
+		if candidate == word {
+			found = true
+		}
+	}
+	if !found || total == 0 {
+		return 0
+	}
+
+	return g.priors.weight(word) / total
+}
@@ -0,0 +1,75 @@
+package terminal
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestNewPriors(t *testing.T) {
+	priors := NewPriors([]WordFrequency{
+		{Word: "unseen", Appearances: 0, Targets: 0},
+		{Word: "target", Appearances: 20, Targets: 20},
+		{Word: "decoy", Appearances: 20, Targets: 0},
+	})
+
+	unseen := priors.weight("unseen")
+	if math.IsNaN(unseen) || math.IsInf(unseen, 0) || unseen <= 0 {
+		t.Fatalf("weight of a word without appearances = %v, want positive", unseen)
+	}
+	if unseen != priors.weight("unknown") {
+		t.Fatalf("weight of a word without appearances = %v, want %v as for unknown word", unseen, priors.weight("unknown"))
+	}
+	if priors.weight("target") <= unseen || priors.weight("decoy") >= unseen {
+		t.Fatalf("weights: target = %v, unseen = %v, decoy = %v, want them in descending order",
+			priors.weight("target"), unseen, priors.weight("decoy"))
+	}
+}
+
+func TestSexyIndexWeightsBuckets(t *testing.T) {
+	uniform := Partition{
+		Buckets: []int{2, 2, 0, 1},
+		Mass:    []float64{0.4, 0.4, 0, 0.2},
+	}
+	weighted := Partition{
+		Buckets:  []int{2, 2, 0, 1},
+		Mass:     []float64{0.7, 0.1, 0, 0.2},
+		Weighted: true,
+	}
+
+	if score := StrategySexyIndex.Score(uniform); score != 0 {
+		t.Fatalf("score of equal buckets = %v, want 0", score)
+	}
+	if score := StrategySexyIndex.Score(weighted); score <= 0 {
+		t.Fatalf("score of buckets with different mass = %v, want positive", score)
+	}
+}
+
+func TestPriorsChangeRanking(t *testing.T) {
+	words := randomWords(rand.New(rand.NewSource(0)), 12, 5)
+
+	plain, err := New(slices.Clone(words))
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty, err := New(slices.Clone(words), WithPriors(Priors{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(plain.AvailableWords(), empty.AvailableWords()) {
+		t.Fatalf("ranking with empty priors = %v, want %v", empty.AvailableWords(), plain.AvailableWords())
+	}
+
+	priors := make(Priors)
+	for _, word := range words[:3] {
+		priors[word] = 5
+	}
+	weighted, err := New(slices.Clone(words), WithPriors(priors))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weighted.AvailableWords()[0] == plain.AvailableWords()[0] {
+		t.Fatalf("first suggestion with priors = %s, want it to differ from %s", weighted.AvailableWords()[0], plain.AvailableWords()[0])
+	}
+}
@@ -0,0 +1,69 @@
+package terminal
+
+// WithProbes enables probe mode: besides available words, the game considers already eliminated ones,
+// and suggests such a probe, when it splits available words strictly better than the best of them.
+// Probes could never be the target, so no probe is suggested, when minimax mode found a guaranteed solution.
+func WithProbes() Option {
+	return func(g *Game) {
+		g.probes = true
+	}
+}
+
+// Probe returns an eliminated word, that is better to submit than the first available word.
+// It reports false, if probe mode is disabled, the game has a guaranteed solution, or no eliminated word splits available words better.
+func (g *Game) Probe() (string, bool) {
+	if g.probe == "" {
+		return "", false
+	}
+	return g.probe, true
+}
+
+// IsProbe reports whether the word was already eliminated, so it could not be the target.
+func (g *Game) IsProbe(word string) bool {
+	word = normalizeWord(word)
+	for _, candidate := range g.availableWords {
+		if candidate == word {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Game) findProbe() {
+	g.probe = ""
+	if !g.probes || g.solution != nil || len(g.availableWords) <= 2 {
+		return
+	}
+
+	candidates := g.candidates()
+
+	best := g.partitionOf(g.availableWords[0], candidates)
+	bestWorst := StrategyWorstCase.Score(best)
+	bestExpected := StrategyExpectedSize.Score(best)
+
+	available := make(map[string]struct{}, len(g.availableWords))
+	for _, word := range g.availableWords {
+		available[word] = struct{}{}
+	}
+
+	for _, word := range g.initialWords {
+		if _, exists := available[word]; exists {
+			continue
+		}
+
+		partition := g.partitionOf(word, candidates)
+		worst := StrategyWorstCase.Score(partition)
+		expected := StrategyExpectedSize.Score(partition)
+
+		if worst > bestWorst || expected > bestExpected {
+			continue
+		}
+		if worst == bestWorst && expected == bestExpected {
+			continue
+		}
+
+		g.probe = word
+		bestWorst = worst
+		bestExpected = expected
+	}
+}
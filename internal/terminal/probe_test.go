@@ -0,0 +1,62 @@
+package terminal
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+var probeWords = []string{"gbhd", "fhcc", "fgac", "bhga", "bdbh", "ggcf", "hcgf", "gdfe"}
+
+func TestProbe(t *testing.T) {
+	game, err := New(slices.Clone(probeWords), WithProbes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	game.SubmitAttempt("bhga", 0)
+
+	probe, ok := game.Probe()
+	if !ok {
+		t.Fatalf("no probe among eliminated words for %v", game.AvailableWords())
+	}
+	if probe != "fhcc" {
+		t.Fatalf("probe = %s, want fhcc", probe)
+	}
+	if !game.IsProbe(probe) || slices.Contains(game.AvailableWords(), probe) {
+		t.Fatalf("probe %s is one of available words %v", probe, game.AvailableWords())
+	}
+
+	// the probe splits candidates in three buckets, while the best candidate leaves three of them together
+	best := game.Partition(game.AvailableWords()[0])
+	split := game.Partition(probe)
+	if StrategyWorstCase.Score(split) >= StrategyWorstCase.Score(best) {
+		t.Fatalf("probe partition %v is not better than %v", split.Buckets, best.Buckets)
+	}
+}
+
+func TestProbeDisabled(t *testing.T) {
+	game, err := New(slices.Clone(probeWords))
+	if err != nil {
+		t.Fatal(err)
+	}
+	game.SubmitAttempt("bhga", 0)
+
+	if probe, ok := game.Probe(); ok {
+		t.Fatalf("probe %s is suggested without probe mode", probe)
+	}
+}
+
+func TestProbeSkippedWithSolution(t *testing.T) {
+	game, err := New(slices.Clone(probeWords), WithProbes(), WithMinimax(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	game.SubmitAttempt("bhga", 0)
+
+	if _, ok := game.Solution(); !ok {
+		t.Fatal("no solution in minimax mode")
+	}
+	if probe, ok := game.Probe(); ok {
+		t.Fatalf("probe %s is suggested, while minimax found a guaranteed solution", probe)
+	}
+}
@@ -0,0 +1,35 @@
+package terminal
+
+import (
+	"errors"
+	"slices"
+)
+
+var ErrUnknownTarget = errors.New("terminal.Simulate(): target is not in the words list")
+
+// Simulate plays the game with known target, always submitting the first suggested word,
+// and returns all submitted attempts. The last attempt is the target itself.
+func Simulate(words []string, target string, opts ...Option) ([]Attempt, error) {
+	list := make([]string, len(words))
+	copy(list, words)
+
+	game, err := New(list, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	target = normalizeWord(target)
+	if !slices.Contains(game.initialWords, target) {
+		return nil, ErrUnknownTarget
+	}
+
+	for len(game.availableWords) != 0 {
+		guess := game.availableWords[0]
+		game.SubmitAttempt(guess, game.matrix.likeness(guess, target))
+		if guess == target {
+			return game.History(), nil
+		}
+	}
+
+	return nil, ErrUnknownTarget
+}
@@ -0,0 +1,239 @@
+package terminal
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+var ErrSolverTimeout = errors.New("terminal.Game.Solve(): time budget exceeded")
+
+// Solution is a result of the game-tree search over available words.
+type Solution struct {
+	// Word is the guess, that minimises the worst-case amount of attempts.
+	Word string
+	// Depth is the worst-case amount of attempts to find the target, including the guess itself.
+	Depth int
+}
+
+// WithMinimax enables game-tree search mode: after each attempt the game searches for the guess,
+// that guarantees the fewest worst-case attempts, and puts it first in available words.
+// If the search does not fit into the time budget, words are ordered by the strategy only.
+func WithMinimax(budget time.Duration) Option {
+	return func(g *Game) {
+		g.minimax = budget
+	}
+}
+
+// Solution returns the result of the last game-tree search. It reports false, if minimax mode is
+// disabled or the search did not fit into the time budget.
+func (g *Game) Solution() (Solution, bool) {
+	if g.solution == nil {
+		return Solution{}, false
+	}
+	return *g.solution, true
+}
+
+// Solve searches for the guess among available words, that minimises the worst-case amount of attempts.
+func (g *Game) Solve(budget time.Duration) (Solution, error) {
+	if len(g.availableWords) == 0 {
+		return Solution{}, nil
+	}
+
+	s := newSolver(g.availableWords, g.matrix, budget)
+
+	candidates := make([]int, len(g.availableWords))
+	for i := range candidates {
+		candidates[i] = i
+	}
+
+	for depth := 1; depth <= len(candidates); depth++ {
+		guess, ok := s.solve(candidates, depth)
+		if s.expired {
+			return Solution{}, ErrSolverTimeout
+		}
+		if ok {
+			return Solution{
+				Word:  g.availableWords[guess],
+				Depth: depth,
+			}, nil
+		}
+	}
+
+	// unreachable: every set of candidates can be solved by guessing them one by one
+	return Solution{}, nil
+}
+
+func (g *Game) applyMinimax() {
+	g.solution = nil
+	if g.minimax <= 0 {
+		return
+	}
+
+	solution, err := g.Solve(g.minimax)
+	if err != nil {
+		return
+	}
+	g.solution = &solution
+
+	for i, word := range g.availableWords {
+		if word == solution.Word {
+			copy(g.availableWords[1:i+1], g.availableWords[:i])
+			g.availableWords[0] = word
+			break
+		}
+	}
+}
+
+// bounds keeps known results for a set of candidates:
+// it's solvable in `solvable` attempts starting with `guess`, and unsolvable in `unsolvable` attempts.
+type bounds struct {
+	solvable   int
+	guess      int
+	unsolvable int
+}
+
+type solver struct {
+	words    []string
+	likeness [][]int
+	memo     map[string]*bounds
+	deadline time.Time
+	calls    int
+	expired  bool
+}
+
+func newSolver(words []string, matrix *likenessMatrix, budget time.Duration) *solver {
+	likeness := make([][]int, len(words))
+	for i := range words {
+		likeness[i] = make([]int, len(words))
+		for j := range words {
+			likeness[i][j] = matrix.likeness(words[i], words[j])
+		}
+	}
+
+	return &solver{
+		words:    words,
+		likeness: likeness,
+		memo:     make(map[string]*bounds),
+		deadline: time.Now().Add(budget),
+	}
+}
+
+// solve reports whether candidates could be solved within depth attempts, and the guess to achieve it.
+func (s *solver) solve(candidates []int, depth int) (int, bool) {
+	if len(candidates) == 1 {
+		return candidates[0], depth >= 1
+	}
+	if depth <= 1 {
+		return 0, false
+	}
+
+	s.calls++
+	if s.calls%1024 == 0 && time.Now().After(s.deadline) {
+		s.expired = true
+	}
+	if s.expired {
+		return 0, false
+	}
+
+	key := s.key(candidates)
+	known, exists := s.memo[key]
+	if !exists {
+		known = &bounds{}
+		s.memo[key] = known
+	}
+	if known.unsolvable >= depth {
+		return 0, false
+	}
+	if known.solvable != 0 && known.solvable <= depth {
+		return known.guess, true
+	}
+
+	for _, guess := range s.order(candidates) {
+		solved := true
+		for _, bucket := range s.split(guess, candidates) {
+			if _, ok := s.solve(bucket, depth-1); !ok {
+				solved = false
+				break
+			}
+		}
+		if s.expired {
+			return 0, false
+		}
+		if solved {
+			if known.solvable == 0 || depth < known.solvable {
+				known.solvable = depth
+				known.guess = guess
+			}
+			return guess, true
+		}
+	}
+
+	if depth > known.unsolvable {
+		known.unsolvable = depth
+	}
+	return 0, false
+}
+
+// split partitions candidates by likeness with the guess, omitting the guess itself.
+func (s *solver) split(guess int, candidates []int) [][]int {
+	buckets := make(map[int][]int)
+	for _, candidate := range candidates {
+		if candidate == guess {
+			continue
+		}
+		likeness := s.likeness[guess][candidate]
+		buckets[likeness] = append(buckets[likeness], candidate)
+	}
+
+	result := make([][]int, 0, len(buckets))
+	for _, bucket := range buckets {
+		result = append(result, bucket)
+	}
+
+	// the largest buckets are the most likely to fail, so check them first
+	sort.Slice(result, func(i, j int) bool {
+		return len(result[i]) > len(result[j])
+	})
+
+	return result
+}
+
+// order returns candidates sorted by the largest bucket they leave, so promising guesses are tried first.
+func (s *solver) order(candidates []int) []int {
+	worst := make(map[int]int, len(candidates))
+	for _, guess := range candidates {
+		sizes := make(map[int]int)
+		for _, candidate := range candidates {
+			if candidate != guess {
+				sizes[s.likeness[guess][candidate]]++
+			}
+		}
+		for _, size := range sizes {
+			if size > worst[guess] {
+				worst[guess] = size
+			}
+		}
+	}
+
+	ordered := make([]int, len(candidates))
+	copy(ordered, candidates)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return worst[ordered[i]] < worst[ordered[j]]
+	})
+
+	return ordered
+}
+
+func (s *solver) key(candidates []int) string {
+	sorted := make([]int, len(candidates))
+	copy(sorted, candidates)
+	sort.Ints(sorted)
+
+	key := make([]byte, 0, 2*len(sorted))
+	for _, candidate := range sorted {
+		key = append(key, byte(candidate>>8), byte(candidate))
+	}
+
+	return string(key)
+}
@@ -0,0 +1,91 @@
+package terminal
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestSolveMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(4))
+
+	for i := 0; i < 200; i++ {
+		words := randomWords(random, 6+random.Intn(7), 4)
+
+		game, err := New(words)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		solution, err := game.Solve(time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := bruteForceDepth(game, game.AvailableWords())
+		if solution.Depth != want {
+			t.Fatalf("game %d %v: depth = %d, want %d", i, words, solution.Depth, want)
+		}
+		if depth := bruteForceGuessDepth(game, solution.Word, game.AvailableWords()); depth != want {
+			t.Fatalf("game %d %v: guess %s needs %d attempts, want %d", i, words, solution.Word, depth, want)
+		}
+	}
+}
+
+func TestMinimaxPutsSolutionFirst(t *testing.T) {
+	words := randomWords(rand.New(rand.NewSource(5)), 12, 4)
+
+	game, err := New(words, WithMinimax(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	solution, ok := game.Solution()
+	if !ok {
+		t.Fatal("no solution in minimax mode")
+	}
+	if game.AvailableWords()[0] != solution.Word {
+		t.Fatalf("first word = %s, want solution %s", game.AvailableWords()[0], solution.Word)
+	}
+}
+
+func TestSolveTimeout(t *testing.T) {
+	words := randomWords(rand.New(rand.NewSource(6)), 200, 8)
+
+	game, err := New(words)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := game.Solve(time.Nanosecond); err != ErrSolverTimeout {
+		t.Fatalf("err = %v, want %v", err, ErrSolverTimeout)
+	}
+}
+
+// bruteForceDepth returns the worst-case amount of attempts to find the target among candidates,
+// trying every candidate as a guess on every step.
+func bruteForceDepth(game *Game, candidates []string) int {
+	best := len(candidates)
+	for _, guess := range candidates {
+		best = min(best, bruteForceGuessDepth(game, guess, candidates))
+	}
+	return best
+}
+
+// bruteForceGuessDepth returns the worst-case amount of attempts to find the target among candidates,
+// starting with the guess.
+func bruteForceGuessDepth(game *Game, guess string, candidates []string) int {
+	buckets := make(map[int][]string)
+	for _, candidate := range candidates {
+		if candidate != guess {
+			likeness := game.matrix.likeness(guess, candidate)
+			buckets[likeness] = append(buckets[likeness], candidate)
+		}
+	}
+
+	depth := 1
+	for _, bucket := range buckets {
+		depth = max(depth, 1+bruteForceDepth(game, bucket))
+	}
+	return depth
+}
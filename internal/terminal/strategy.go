@@ -0,0 +1,176 @@
+package terminal
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrUnknownStrategy = errors.New("terminal.StrategyByName(): unknown strategy")
+
+// Strategy ranks possible guesses by the partition they produce over the candidate words.
+// Words with lower score are suggested first.
+type Strategy interface {
+	Name() string
+	Score(p Partition) float64
+}
+
+// Partition describes how a guess splits the candidate words:
+// Buckets[n] is the amount of candidates, that have exactly n matched letters with the guess,
+// and Mass[n] is the probability, that the target is one of them.
+// Weighted reports whether candidates have different probabilities to be the target, given by priors.
+type Partition struct {
+	Guess    string
+	Buckets  []int
+	Mass     []float64
+	Weighted bool
+}
+
+// Total returns the amount of candidates in the partition.
+func (p Partition) Total() int {
+	total := 0
+	for _, size := range p.Buckets {
+		total += size
+	}
+	return total
+}
+
+// remaining returns buckets of candidates, that still left after the guess was not a target.
+func (p Partition) remaining() []int {
+	return p.Buckets[:len(p.Buckets)-1]
+}
+
+var (
+	// StrategySexyIndex orders words by difference between the largest and the average bucket.
+	// With priors buckets are measured by the probability of their candidates to be the target.
+	StrategySexyIndex Strategy = sexyIndex{}
+	// StrategyExpectedSize orders words by expected amount of candidates left after the guess,
+	// weighted by the probability of each candidate to be the target.
+	StrategyExpectedSize Strategy = expectedSize{}
+	// StrategyEntropy orders words by Shannon entropy of the likeness partition, the highest first.
+	// Probabilities of likeness values are weighted by the probability of each candidate to be the target.
+	StrategyEntropy Strategy = entropy{}
+	// StrategyWorstCase orders words by the largest amount of candidates, that could be left after the guess.
+	StrategyWorstCase Strategy = worstCase{}
+)
+
+// Strategies returns all built-in strategies.
+func Strategies() []Strategy {
+	return []Strategy{StrategySexyIndex, StrategyExpectedSize, StrategyEntropy, StrategyWorstCase}
+}
+
+// StrategyByName returns built-in strategy with provided name.
+func StrategyByName(name string) (Strategy, error) {
+	for _, strategy := range Strategies() {
+		if strategy.Name() == name {
+			return strategy, nil
+		}
+	}
+	return nil, ErrUnknownStrategy
+}
+
+type sexyIndex struct{}
+
+func (sexyIndex) Name() string {
+	return "sexy-index"
+}
+
+func (s sexyIndex) Score(p Partition) float64 {
+	if p.Weighted {
+		return s.weightedScore(p)
+	}
+
+	sum := 0
+	max := 0
+	buckets := 0
+	for _, size := range p.remaining() {
+		if size == 0 {
+			continue
+		}
+		buckets++
+		sum += size
+		if size > max {
+			max = size
+		}
+	}
+	if buckets == 0 {
+		return 0
+	}
+	average := sum / buckets
+
+	return float64(max - average)
+}
+
+// weightedScore measures each bucket by expected amount of candidates in it, so the bucket with likely
+// targets weighs more than the bucket of the same size with unlikely ones.
+func (sexyIndex) weightedScore(p Partition) float64 {
+	total := float64(p.Total())
+
+	sum := 0.0
+	max := 0.0
+	buckets := 0
+	for n, size := range p.remaining() {
+		if size == 0 {
+			continue
+		}
+		weight := p.Mass[n] * total
+		buckets++
+		sum += weight
+		if weight > max {
+			max = weight
+		}
+	}
+	if buckets == 0 {
+		return 0
+	}
+
+	return max - sum/float64(buckets)
+}
+
+type expectedSize struct{}
+
+func (expectedSize) Name() string {
+	return "expected-size"
+}
+
+func (expectedSize) Score(p Partition) float64 {
+	value := 0.0
+	for n, size := range p.remaining() {
+		value += p.Mass[n] * float64(size)
+	}
+
+	return value
+}
+
+type entropy struct{}
+
+func (entropy) Name() string {
+	return "entropy"
+}
+
+func (entropy) Score(p Partition) float64 {
+	value := 0.0
+	for _, probability := range p.Mass {
+		if probability == 0 {
+			continue
+		}
+		value -= probability * math.Log2(probability)
+	}
+
+	return -value
+}
+
+type worstCase struct{}
+
+func (worstCase) Name() string {
+	return "worst-case"
+}
+
+func (worstCase) Score(p Partition) float64 {
+	max := 0
+	for _, size := range p.remaining() {
+		if size > max {
+			max = size
+		}
+	}
+	return float64(max)
+}
@@ -7,25 +7,64 @@ import (
 	"sort"
 	"strings"
 	"terminal/pkg/slice"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 var (
 	ErrDifferentWordsLength = errors.New("terminal.Game.New(): words could not be different length")
 	ErrInsufficientWords    = errors.New("terminal.Game.New(): insufficient words list")
+	ErrNoAttempts           = errors.New("terminal.Game.Undo(): no attempts to undo")
+	ErrAttemptOutOfRange    = errors.New("terminal.Game.RewindTo(): attempt number out of range")
 )
 
 type Game struct {
 	initialWords   []string
 	availableWords []string
-	attempts       []*attempt
+	attempts       []Attempt
+	strategy       Strategy
+	minimax        time.Duration
+	solution       *Solution
+	priors         Priors
+	budget         int
+	matrix         *likenessMatrix
+	probes         bool
+	probe          string
 }
 
-type attempt struct {
-	word           string
-	guessedLetters int
+// Option configures a Game on creation.
+type Option func(*Game)
+
+// WithStrategy sets the strategy used to order available words. StrategySexyIndex is used by default.
+func WithStrategy(strategy Strategy) Option {
+	return func(g *Game) {
+		g.strategy = strategy
+	}
+}
+
+// Attempt is a word, submitted by user, with amount of letters guessed in it.
+type Attempt struct {
+	Word           string
+	GuessedLetters int
 }
 
-func New(words []string) (*Game, error) {
+func New(words []string, opts ...Option) (*Game, error) {
+	game, err := newGame(words, opts...)
+	if err != nil {
+		return nil, err
+	}
+	game.rank()
+
+	return game, nil
+}
+
+// newGame creates a game without ranking its words, so attempts could be added before the first ranking.
+func newGame(words []string, opts ...Option) (*Game, error) {
+	for i := range words {
+		words[i] = normalizeWord(words[i])
+	}
+
 	if !isWordsEqualLength(words) {
 		return nil, ErrDifferentWordsLength
 	}
@@ -34,16 +73,20 @@ func New(words []string) (*Game, error) {
 		return nil, ErrInsufficientWords
 	}
 
-	for i := range words {
-		words[i] = strings.TrimSpace(strings.ToLower(words[i]))
-	}
+	available := make([]string, len(words))
+	copy(available, words)
 
 	game := &Game{
 		initialWords:   words,
-		availableWords: words,
-		attempts:       make([]*attempt, 0),
+		availableWords: available,
+		attempts:       make([]Attempt, 0),
+		strategy:       StrategySexyIndex,
+		budget:         DefaultAttemptBudget,
+		matrix:         newLikenessMatrix(words),
+	}
+	for _, opt := range opts {
+		opt(game)
 	}
-	game.sortWordsBySexyIndex()
 
 	return game, nil
 }
@@ -56,6 +99,10 @@ func (g *Game) AvailableWords() []string {
 	return g.availableWords
 }
 
+func (g *Game) Strategy() Strategy {
+	return g.strategy
+}
+
 func (g *Game) Target() string {
 	if len(g.availableWords) != 1 {
 		return ""
@@ -63,10 +110,17 @@ func (g *Game) Target() string {
 	return g.availableWords[0]
 }
 
+// History returns all submitted attempts in order of submission.
+func (g *Game) History() []Attempt {
+	history := make([]Attempt, len(g.attempts))
+	copy(history, g.attempts)
+	return history
+}
+
 func (g *Game) Attempts() int {
 	n := 0
 	for _, attempt := range g.attempts {
-		if attempt.guessedLetters != len(g.initialWords[0]) {
+		if attempt.GuessedLetters != g.wordLength() {
 			n++
 		}
 	}
@@ -74,25 +128,54 @@ func (g *Game) Attempts() int {
 }
 
 func (g *Game) SubmitAttempt(word string, guessedLetters int) {
-	a := attempt{
-		word:           word,
-		guessedLetters: guessedLetters,
-	}
-	g.attempts = append(g.attempts, &a)
+	g.attempts = append(g.attempts, Attempt{
+		Word:           normalizeWord(word),
+		GuessedLetters: guessedLetters,
+	})
 	g.updateWords()
-	g.sortWordsBySexyIndex()
+	g.rank()
+}
+
+// Undo removes the last submitted attempt and returns it.
+func (g *Game) Undo() (Attempt, error) {
+	if len(g.attempts) == 0 {
+		return Attempt{}, ErrNoAttempts
+	}
+
+	last := g.attempts[len(g.attempts)-1]
+	g.attempts = g.attempts[:len(g.attempts)-1]
+	g.replay()
+
+	return last, nil
+}
+
+// RewindTo keeps only first n submitted attempts and discards the rest.
+func (g *Game) RewindTo(n int) error {
+	if n < 0 || n > len(g.attempts) {
+		return ErrAttemptOutOfRange
+	}
+
+	g.attempts = g.attempts[:n]
+	g.replay()
+
+	return nil
 }
 
 func RemoveTrashFromWordList(words []string) []string {
 	cleaned := make([]string, 0)
 	for _, word := range words {
+		if strings.TrimSpace(word) == "" {
+			continue
+		}
 		if strings.Contains(word, "NaN") {
 			continue
 		}
-		if word[0] == '(' || word[0] == '{' || word[0] == '[' {
+		first, _ := utf8.DecodeRuneInString(word)
+		if first == '(' || first == '{' || first == '[' {
 			continue
 		}
-		cleaned = append(cleaned, word)
+		// words are normalized before deduplication, so the same word in different case is kept once
+		cleaned = append(cleaned, normalizeWord(word))
 	}
 	return slice.Unique(cleaned)
 }
@@ -111,12 +194,28 @@ func ComputeWordsHash(words []string) string {
 	return hex.EncodeToString(checksum[:])
 }
 
+// isValidAttempt reports whether the attempt word is in the list, and amount of guessed letters fits the word length.
+func (g *Game) isValidAttempt(attempt Attempt) bool {
+	if _, exists := g.matrix.index[attempt.Word]; !exists {
+		return false
+	}
+	return attempt.GuessedLetters >= 0 && attempt.GuessedLetters <= g.wordLength()
+}
+
+// replay recomputes available words from the initial ones using all submitted attempts.
+func (g *Game) replay() {
+	g.availableWords = make([]string, len(g.initialWords))
+	copy(g.availableWords, g.initialWords)
+	g.updateWords()
+	g.rank()
+}
+
 func (g *Game) updateWords() {
 	updated := make([]string, 0)
 	for _, word := range g.availableWords {
 		fits := true
 		for _, tried := range g.attempts {
-			if !compareWordsMatchedLetters(word, tried.word, tried.guessedLetters) {
+			if g.matrix.likeness(word, tried.Word) != tried.GuessedLetters {
 				fits = false
 				break
 			}
@@ -128,61 +227,168 @@ func (g *Game) updateWords() {
 	g.availableWords = updated
 }
 
-func (g *Game) sortWordsBySexyIndex() {
+func (g *Game) rank() {
+	g.sortWords()
+	g.applyMinimax()
+	g.findProbe()
+}
+
+func (g *Game) sortWords() {
+	candidates := g.candidates()
+
+	scores := make(map[string]float64, len(g.availableWords))
+	for _, word := range g.availableWords {
+		scores[word] = g.strategy.Score(g.partitionOf(word, candidates))
+	}
+
+	// words with equal scores keep order of the initial list, so ranking does not depend on the previous one,
+	// and replaying attempts after undo ranks words the same way as submitting them
 	sort.Slice(g.availableWords, func(i, j int) bool {
-		return countWordSexyIndex(g.availableWords[i], g.availableWords) < countWordSexyIndex(g.availableWords[j], g.availableWords)
+		a, b := g.availableWords[i], g.availableWords[j]
+		if scores[a] != scores[b] {
+			return scores[a] < scores[b]
+		}
+		return g.matrix.index[a] < g.matrix.index[b]
 	})
 }
 
-func countWordSexyIndex(target string, words []string) int {
-	matches := make(map[int]int, 0) // key: matches in word, value: words with this amount of matches
-	for i := 0; i < len(words); i++ {
-		if words[i] == target {
-			continue
-		}
+// candidates keeps available words with their positions in the likeness matrix and target weights.
+type candidates struct {
+	words   []string
+	indexes []int
+	weights []float64
+}
+
+func (g *Game) candidates() candidates {
+	// candidates are kept in order of the initial list, so sums of their weights don't depend on the ranking
+	words := make([]string, len(g.availableWords))
+	copy(words, g.availableWords)
+	sort.Slice(words, func(i, j int) bool {
+		return g.matrix.index[words[i]] < g.matrix.index[words[j]]
+	})
 
-		matches[countMatchedLetters(target, words[i])]++
+	c := candidates{
+		words:   words,
+		indexes: g.matrix.indexes(words),
+		weights: make([]float64, len(words)),
 	}
+	for i, word := range words {
+		c.weights[i] = g.priors.weight(word)
+	}
+	return c
+}
 
-	sum := 0
-	max := 0
-	for _, v := range matches {
-		sum += v
-		if v > max {
-			max = v
+// Partition returns how the guess would split available words by amount of matched letters.
+func (g *Game) Partition(guess string) Partition {
+	return g.partition(normalizeWord(guess))
+}
+
+// Remaining returns available words, that would be left after the guess with provided amount of matched letters.
+func (g *Game) Remaining(guess string, guessedLetters int) []string {
+	guess = normalizeWord(guess)
+
+	remaining := make([]string, 0)
+	for _, word := range g.availableWords {
+		if g.matrix.likeness(guess, word) == guessedLetters {
+			remaining = append(remaining, word)
 		}
 	}
-	if len(matches) == 0 {
-		return 0
-	}
-	average := sum / len(matches)
-	sexyIndex := max - average
+	return remaining
+}
 
-	return sexyIndex
+func (g *Game) partition(guess string) Partition {
+	return g.partitionOf(guess, g.candidates())
 }
 
-func compareWordsMatchedLetters(a string, b string, expected int) bool {
-	return expected == countMatchedLetters(a, b)
+func (g *Game) partitionOf(guess string, c candidates) Partition {
+	buckets := make([]int, utf8.RuneCountInString(guess)+1)
+	mass := make([]float64, utf8.RuneCountInString(guess)+1)
+	total := 0.0
+
+	var row []uint8
+	if index, exists := g.matrix.index[guess]; exists {
+		row = g.matrix.values[index]
+	}
+
+	for i, word := range c.words {
+		var matched int
+		if row != nil && c.indexes[i] != -1 {
+			matched = int(row[c.indexes[i]])
+		} else {
+			matched = countMatchedLetters(guess, word)
+		}
+
+		buckets[matched]++
+		mass[matched] += c.weights[i]
+		total += c.weights[i]
+	}
+
+	if total > 0 {
+		for i := range mass {
+			mass[i] /= total
+		}
+	}
+
+	return Partition{
+		Guess:    guess,
+		Buckets:  buckets,
+		Mass:     mass,
+		Weighted: len(g.priors) != 0,
+	}
 }
 
+// countMatchedLetters returns amount of positions, where words have the same letter, ignoring case.
 func countMatchedLetters(a, b string) int {
 	value := 0
-	for i := 0; i < len(a); i++ {
-		if a[i] == b[i] {
+	runesB := []rune(b)
+	i := 0
+	for _, r := range a {
+		if i >= len(runesB) {
+			break
+		}
+		if equalFold(r, runesB[i]) {
 			value++
 		}
+		i++
 	}
 	return value
 }
 
+// equalFold reports whether runes are equal under Unicode case folding, like σ, ς and Σ.
+func equalFold(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeWord trims spaces around the word and converts it to lower case. It's not a full case folding,
+// so letters with several lower case forms, like σ and ς, are matched by equalFold.
+func normalizeWord(word string) string {
+	return strings.ToLower(strings.TrimSpace(word))
+}
+
+// wordLength returns amount of letters in words of the game.
+func (g *Game) wordLength() int {
+	if len(g.initialWords) == 0 {
+		return 0
+	}
+	return utf8.RuneCountInString(g.initialWords[0])
+}
+
 func isWordsEqualLength(words []string) bool {
 	if len(words) == 0 {
 		return true
 	}
 
-	expectedLength := len(words[0])
+	expectedLength := utf8.RuneCountInString(words[0])
 	for _, word := range words[1:] {
-		if len(word) != expectedLength {
+		if utf8.RuneCountInString(word) != expectedLength {
 			return false
 		}
 	}
@@ -0,0 +1,70 @@
+package terminal
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestUndoRestoresRanking(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		words := randomWords(random, 6+random.Intn(30), 6)
+		target := words[random.Intn(len(words))]
+
+		game, err := New(words)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for len(game.AvailableWords()) > 1 {
+			before := slices.Clone(game.AvailableWords())
+
+			guess := before[0]
+			game.SubmitAttempt(guess, game.matrix.likeness(guess, target))
+			if _, err := game.Undo(); err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(before, game.AvailableWords()) {
+				t.Fatalf("game %d: ranking after undo = %v, want %v", i, game.AvailableWords(), before)
+			}
+
+			game.SubmitAttempt(guess, game.matrix.likeness(guess, target))
+		}
+	}
+}
+
+func TestRewindTo(t *testing.T) {
+	words := randomWords(rand.New(rand.NewSource(2)), 30, 6)
+	target := words[len(words)-1]
+
+	game, err := New(words)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rankings := [][]string{slices.Clone(game.AvailableWords())}
+	for len(game.AvailableWords()) > 1 {
+		guess := game.AvailableWords()[0]
+		game.SubmitAttempt(guess, game.matrix.likeness(guess, target))
+		rankings = append(rankings, slices.Clone(game.AvailableWords()))
+	}
+
+	for n := len(rankings) - 1; n >= 0; n-- {
+		if err := game.RewindTo(n); err != nil {
+			t.Fatal(err)
+		}
+		if len(game.History()) != n {
+			t.Fatalf("RewindTo(%d): %d attempts left", n, len(game.History()))
+		}
+		if !slices.Equal(rankings[n], game.AvailableWords()) {
+			t.Fatalf("RewindTo(%d): ranking = %v, want %v", n, game.AvailableWords(), rankings[n])
+		}
+	}
+
+	if err := game.RewindTo(1); err != ErrAttemptOutOfRange {
+		t.Fatalf("RewindTo(1) on a game without attempts: err = %v, want %v", err, ErrAttemptOutOfRange)
+	}
+}
@@ -0,0 +1,106 @@
+package terminal
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidAttempt = errors.New("terminal.NewFromTranscript(): attempt word is not in the list or has invalid amount of guessed letters")
+
+var (
+	// matches `word 2` and `word 2/6`
+	attemptLine = regexp.MustCompile(`^>?\s*(\p{L}+)\s+(\d+)(?:\s*/\s*\d+)?$`)
+	// matches `>word ... Likeness=2` in one line
+	attemptLikenessLine = regexp.MustCompile(`(?i)^>\s*(\p{L}+)\b.*likeness\s*[=:]\s*(\d+)$`)
+	// matches `>Likeness=2`, that follows `>word` line
+	likenessLine = regexp.MustCompile(`(?i)^>?\s*likeness\s*[=:]\s*(\d+)$`)
+	// matches `>word`
+	loggedWordLine = regexp.MustCompile(`^>\s*(\p{L}+)$`)
+)
+
+// Transcript is a word list, followed by attempts, copied from the in-game log.
+type Transcript struct {
+	Words    []string
+	Attempts []Attempt
+}
+
+// ParseTranscript splits text into the word list and attempts. Supported attempt formats are
+// `word 2`, `word 2/6` and `>word ... Likeness=2`, where the likeness could be on the following lines.
+func ParseTranscript(text string) Transcript {
+	transcript := Transcript{
+		Words:    make([]string, 0),
+		Attempts: make([]Attempt, 0),
+	}
+
+	pending := ""
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if match := attemptLine.FindStringSubmatch(line); match != nil {
+			transcript.addAttempt(match[1], match[2])
+			continue
+		}
+		if match := attemptLikenessLine.FindStringSubmatch(line); match != nil {
+			transcript.addAttempt(match[1], match[2])
+			pending = ""
+			continue
+		}
+		if match := likenessLine.FindStringSubmatch(line); match != nil {
+			if pending != "" {
+				transcript.addAttempt(pending, match[1])
+				pending = ""
+			}
+			continue
+		}
+		if match := loggedWordLine.FindStringSubmatch(line); match != nil {
+			pending = match[1]
+			continue
+		}
+		if strings.HasPrefix(line, ">") {
+			// other log lines, e.g. `>Entry denied`
+			continue
+		}
+
+		transcript.Words = append(transcript.Words, line)
+	}
+
+	return transcript
+}
+
+func (t *Transcript) addAttempt(word string, guessedLetters string) {
+	n, err := strconv.Atoi(guessedLetters)
+	if err != nil {
+		return
+	}
+	t.Attempts = append(t.Attempts, Attempt{
+		Word:           normalizeWord(word),
+		GuessedLetters: n,
+	})
+}
+
+// NewFromTranscript creates a game from the word list in transcript and replays its attempts.
+func NewFromTranscript(text string, opts ...Option) (*Game, error) {
+	transcript := ParseTranscript(text)
+
+	game, err := New(RemoveTrashFromWordList(transcript.Words), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attempt := range transcript.Attempts {
+		if !game.isValidAttempt(attempt) {
+			return nil, ErrInvalidAttempt
+		}
+	}
+
+	for _, attempt := range transcript.Attempts {
+		game.SubmitAttempt(attempt.Word, attempt.GuessedLetters)
+	}
+
+	return game, nil
+}
@@ -0,0 +1,103 @@
+package terminal
+
+import (
+	"slices"
+	"testing"
+)
+
+const transcriptWords = "stone\nshore\nstove\nscore\nspoke\nsmoke\n"
+
+func TestParseTranscript(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		attempts []Attempt
+	}{
+		{
+			name:     "word and likeness",
+			text:     transcriptWords + "smoke 2\nstove 3",
+			attempts: []Attempt{{Word: "smoke", GuessedLetters: 2}, {Word: "stove", GuessedLetters: 3}},
+		},
+		{
+			name:     "word and likeness of word length",
+			text:     transcriptWords + "smoke 2/5\nstove 3 / 5",
+			attempts: []Attempt{{Word: "smoke", GuessedLetters: 2}, {Word: "stove", GuessedLetters: 3}},
+		},
+		{
+			name:     "logged word with likeness",
+			text:     transcriptWords + ">SMOKE Entry denied. Likeness=2\n>stove likeness: 3",
+			attempts: []Attempt{{Word: "smoke", GuessedLetters: 2}, {Word: "stove", GuessedLetters: 3}},
+		},
+		{
+			name:     "logged word with likeness on the following lines",
+			text:     transcriptWords + ">SMOKE\n>Entry denied.\n>Likeness=2\n>stove\n>Likeness=3",
+			attempts: []Attempt{{Word: "smoke", GuessedLetters: 2}, {Word: "stove", GuessedLetters: 3}},
+		},
+		{
+			name:     "ignored lines",
+			text:     "\n  \n>Welcome to ROBCO Industries\n>Likeness=1\n" + transcriptWords + ">Entry denied.\n\n",
+			attempts: []Attempt{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transcript := ParseTranscript(tt.text)
+
+			if want := []string{"stone", "shore", "stove", "score", "spoke", "smoke"}; !slices.Equal(transcript.Words, want) {
+				t.Fatalf("words = %v, want %v", transcript.Words, want)
+			}
+			if !slices.Equal(transcript.Attempts, tt.attempts) {
+				t.Fatalf("attempts = %v, want %v", transcript.Attempts, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestNewFromTranscript(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		available []string
+		err       error
+	}{
+		{
+			name:      "consistent attempts",
+			text:      transcriptWords + "smoke 3\nstove 4",
+			available: []string{"stone"},
+		},
+		{
+			name:      "contradicting attempts",
+			text:      transcriptWords + "smoke 3\nstove 1",
+			available: []string{},
+		},
+		{
+			name: "likeness above word length",
+			text: transcriptWords + "smoke 6",
+			err:  ErrInvalidAttempt,
+		},
+		{
+			name: "word not in the list",
+			text: transcriptWords + "spore 2",
+			err:  ErrInvalidAttempt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := NewFromTranscript(tt.text)
+			if err != tt.err {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+
+			available := slices.Clone(game.AvailableWords())
+			slices.Sort(available)
+			if !slices.Equal(available, tt.available) {
+				t.Fatalf("available words = %v, want %v", available, tt.available)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+INSERT INTO games (id, telegram_id, words, target, attempts_amount, words_hash, created_at)
+SELECT id, telegram_id, words, target, attempts_amount, words_hash, created_at FROM games_quarantine;
+
+DROP TABLE IF EXISTS games_quarantine;
+ALTER TABLE games DROP COLUMN IF EXISTS flagged;
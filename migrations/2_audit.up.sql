@@ -0,0 +1,12 @@
+ALTER TABLE games ADD COLUMN IF NOT EXISTS flagged bool DEFAULT false NOT NULL;
+
+CREATE TABLE IF NOT EXISTS games_quarantine (
+	id uuid NOT NULL UNIQUE,
+	telegram_id bigint NOT NULL,
+	words text[] NOT NULL,
+	target text NOT NULL,
+	attempts_amount int NOT NULL,
+	words_hash text NOT NULL,
+	created_at timestamp NOT NULL,
+	quarantined_at timestamp DEFAULT now() NOT NULL
+);
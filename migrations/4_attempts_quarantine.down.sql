@@ -0,0 +1,13 @@
+-- attempts could be kept only with their games, so quarantined games with attempts are restored first
+INSERT INTO games (id, telegram_id, words, target, attempts_amount, words_hash, created_at)
+SELECT id, telegram_id, words, target, attempts_amount, words_hash, created_at FROM games_quarantine
+WHERE id IN (SELECT game_id FROM game_attempts_quarantine)
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO game_attempts (game_id, number, word, guessed_letters)
+SELECT game_id, number, word, guessed_letters FROM game_attempts_quarantine
+ON CONFLICT (game_id, number) DO NOTHING;
+
+DELETE FROM games_quarantine WHERE id IN (SELECT game_id FROM game_attempts_quarantine);
+
+DROP TABLE IF EXISTS game_attempts_quarantine;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS game_attempts_quarantine (
+	game_id uuid NOT NULL,
+	number int NOT NULL,
+	word text NOT NULL,
+	guessed_letters int NOT NULL,
+	PRIMARY KEY (game_id, number),
+	FOREIGN KEY (game_id) REFERENCES games_quarantine(id) ON DELETE CASCADE
+);
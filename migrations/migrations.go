@@ -0,0 +1,27 @@
+// Package migrations embeds SQL migrations of the storage backends, so they are applied by the binary itself.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed *.sql
+var postgres embed.FS
+
+//go:embed sqlite/*.sql
+var sqlite embed.FS
+
+// Postgres are migrations of the postgres storage.
+var Postgres fs.FS = postgres
+
+// SQLite are migrations of the sqlite storage.
+var SQLite fs.FS = mustSub(sqlite, "sqlite")
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
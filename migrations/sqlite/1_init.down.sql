@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS game_attempts_quarantine;
+DROP TABLE IF EXISTS game_attempts;
+DROP TABLE IF EXISTS games_quarantine;
+DROP TABLE IF EXISTS games;
+DROP TABLE IF EXISTS users;
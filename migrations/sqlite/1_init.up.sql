@@ -0,0 +1,53 @@
+CREATE TABLE IF NOT EXISTS users (
+    id text DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))) NOT NULL PRIMARY KEY,
+    telegram_id integer NOT NULL UNIQUE,
+    username text NOT NULL UNIQUE,
+    firstname text DEFAULT '' NOT NULL,
+    lastname text DEFAULT '' NOT NULL,
+    is_admin boolean DEFAULT false NOT NULL,
+    created_at timestamp NOT NULL
+);
+
+-- words are stored as JSON arrays
+CREATE TABLE IF NOT EXISTS games (
+	id text DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))) NOT NULL PRIMARY KEY,
+	telegram_id integer NOT NULL,
+	words text NOT NULL,
+	target text NOT NULL,
+	attempts_amount integer NOT NULL,
+	words_hash text NOT NULL,
+	flagged boolean DEFAULT false NOT NULL,
+	created_at timestamp NOT NULL,
+	FOREIGN KEY (telegram_id) REFERENCES users(telegram_id)
+);
+
+CREATE INDEX IF NOT EXISTS games_words_hash ON games (words_hash);
+
+CREATE TABLE IF NOT EXISTS games_quarantine (
+	id text NOT NULL PRIMARY KEY,
+	telegram_id integer NOT NULL,
+	words text NOT NULL,
+	target text NOT NULL,
+	attempts_amount integer NOT NULL,
+	words_hash text NOT NULL,
+	created_at timestamp NOT NULL,
+	quarantined_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS game_attempts (
+	game_id text NOT NULL,
+	number integer NOT NULL,
+	word text NOT NULL,
+	guessed_letters integer NOT NULL,
+	PRIMARY KEY (game_id, number),
+	FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS game_attempts_quarantine (
+	game_id text NOT NULL,
+	number integer NOT NULL,
+	word text NOT NULL,
+	guessed_letters integer NOT NULL,
+	PRIMARY KEY (game_id, number),
+	FOREIGN KEY (game_id) REFERENCES games_quarantine(id) ON DELETE CASCADE
+);